@@ -2,18 +2,13 @@ package main
 
 import (
 	"encoding/hex"
-	"fmt"
 	"log"
 	"net/http"
-	"net/http/httptest"
 	"os"
 
-	"github.com/brattonross/ghostedbot/internal/checkem"
+	"github.com/brattonross/ghostedbot/config"
+	"github.com/brattonross/ghostedbot/internal/bot"
 	"github.com/brattonross/ghostedbot/internal/debug"
-	"github.com/brattonross/ghostedbot/internal/discord"
-	"github.com/brattonross/ghostedbot/internal/mdn"
-	"github.com/brattonross/ghostedbot/internal/words"
-	"github.com/brattonross/ghostedbot/internal/year/progress"
 )
 
 func main() {
@@ -25,42 +20,19 @@ func main() {
 		log.Fatalf("failed to decode public key: %s\n", err)
 	}
 
-	handler := discord.NewInteractionsHandler(pb)
+	handler := bot.NewHandler(pb)
 
-	handler.RegisterApplicationCommandHandler("checkem", checkem.Handler)
-	handler.RegisterApplicationCommandHandler("left-pad", words.LeftPadHandler)
-	handler.RegisterApplicationCommandHandler("mdn", mdn.SearchHandler)
-
-	handler.RegisterApplicationCommandHandler("shuffle", words.ShuffleHandler)
-
-	handler.RegisterApplicationCommandHandler("test", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
-		return discord.MessageResponse("test successful <:AlienUnpleased:940285855292080149>"), nil
-	})
-
-	handler.RegisterApplicationCommandHandler("version", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
-		return discord.MessageResponse(fmt.Sprintf("Built %s using commit %s", debug.FormattedBuildDate(), debug.BuildHash)), nil
-	})
-
-	handler.RegisterApplicationCommandHandler("year-progress", progress.PercentageHandler)
-
-	http.HandleFunc("/interactions", func(w http.ResponseWriter, r *http.Request) {
-		// TODO: Why do the interaction responses only become valid after
-		// we use a recorder to write the response?
-		rec := httptest.NewRecorder()
-		handler.ServeHTTP(rec, r)
-
-		for k, v := range rec.Header() {
-			w.Header()[k] = v
-		}
+	commands, err := config.ApplicationCommands()
+	if err != nil {
+		log.Fatalf("failed to read application commands: %s\n", err)
+	}
 
-		w.WriteHeader(rec.Code)
+	err = handler.ValidateCommands(commands.All())
+	if err != nil {
+		log.Fatalf("application commands do not match the registered handlers:\n%s\n", err)
+	}
 
-		log.Printf("response body: %s\n", rec.Body.String())
-		_, err := rec.Body.WriteTo(w)
-		if err != nil {
-			log.Printf("failed to write response body: %s\n", err)
-		}
-	})
+	http.Handle("/interactions", handler)
 
 	log.Printf("starting roastedbot: built %s using commit with SHA %s\n", debug.FormattedBuildDate(), debug.BuildHash)
 
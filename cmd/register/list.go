@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+// list prints the commands that are currently deployed, either globally or in a guild.
+func list(ctx context.Context, client *discord.Client, applicationId string, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	guildId := flags.String("guild", "", "list the commands in this guild instead of the global commands")
+	asJSON := flags.Bool("json", false, "print the full commands as JSON")
+	flags.Parse(args)
+
+	var commands []*discord.ApplicationCommand
+	var err error
+	if *guildId != "" {
+		commands, err = client.ApplicationCommands.ListGuild(ctx, applicationId, *guildId, true)
+	} else {
+		commands, err = client.ApplicationCommands.List(ctx, applicationId, true)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list application commands: %w", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+		return enc.Encode(commands)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tNAME\tVERSION\tDESCRIPTION")
+	for _, command := range commands {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", command.Id, discord.CommandTypeName(command.Type), command.Name, command.Version, command.Description)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/brattonross/ghostedbot/config"
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+// errDrift is returned by plan when the deployed commands differ from the spec.
+var errDrift = errors.New("deployed application commands differ from the spec")
+
+type action string
+
+const (
+	actionCreate action = "create"
+	actionEdit   action = "edit"
+	actionDelete action = "delete"
+)
+
+// change is a single call needed to bring the deployed commands in line with the spec.
+type change struct {
+	Action action
+	Name   string
+	// Desired is the command from the spec, and is nil when deleting.
+	Desired *discord.RegisterApplicationCommandOptions
+	// Deployed is the command that is currently deployed, and is nil when creating.
+	Deployed *discord.ApplicationCommand
+	// Diffs describes what is different about an edited command.
+	Diffs []string
+}
+
+// scopePlan holds the changes needed in one scope, either the global commands or a guild's commands.
+type scopePlan struct {
+	// GuildId is empty for global commands.
+	GuildId string
+	Changes []change
+}
+
+func (p *scopePlan) String() string {
+	if p.GuildId == "" {
+		return "global"
+	}
+	return "guild " + p.GuildId
+}
+
+// commandKey identifies a command within a scope. Commands of different types may share a name.
+type commandKey struct {
+	Type int
+	Name string
+}
+
+func commandType(t *int) int {
+	if t == nil {
+		return discord.ApplicationCommandTypeChatInput
+	}
+	return *t
+}
+
+// computePlan returns the changes needed to turn the deployed commands into the desired ones,
+// ordered by the desired commands followed by the commands to delete.
+func computePlan(desired []*discord.RegisterApplicationCommandOptions, deployed []*discord.ApplicationCommand) []change {
+	deployedByKey := make(map[commandKey]*discord.ApplicationCommand, len(deployed))
+	for _, command := range deployed {
+		deployedByKey[commandKey{command.Type, command.Name}] = command
+	}
+
+	var changes []change
+	seen := make(map[commandKey]bool, len(desired))
+	for _, command := range desired {
+		key := commandKey{commandType(command.Type), command.Name}
+		seen[key] = true
+
+		current, ok := deployedByKey[key]
+		if !ok {
+			changes = append(changes, change{Action: actionCreate, Name: command.Name, Desired: command})
+			continue
+		}
+
+		diffs := diffCommand(command, current)
+		if len(diffs) > 0 {
+			changes = append(changes, change{Action: actionEdit, Name: command.Name, Desired: command, Deployed: current, Diffs: diffs})
+		}
+	}
+
+	for _, command := range deployed {
+		if !seen[commandKey{command.Type, command.Name}] {
+			changes = append(changes, change{Action: actionDelete, Name: command.Name, Deployed: command})
+		}
+	}
+
+	return changes
+}
+
+// diffCommand describes the differences between a command in the spec and the deployed command.
+func diffCommand(desired *discord.RegisterApplicationCommandOptions, deployed *discord.ApplicationCommand) []string {
+	var diffs []string
+	diffs = diffValue(diffs, "description", stringValue(desired.Description), deployed.Description)
+	diffs = diffValue(diffs, "name_localizations", formatLocalizations(desired.NameLocalizations), formatLocalizations(deployed.NameLocalizations))
+	diffs = diffValue(diffs, "description_localizations", formatLocalizations(desired.DescriptionLocalizations), formatLocalizations(deployed.DescriptionLocalizations))
+	diffs = diffValue(diffs, "default_member_permissions", permissionsValue(desired.DefaultMemberPermissions), permissionsValue(deployed.DefaultMemberPermissions))
+	// Discord defaults dm_permission to true, and may leave it out for commands that never set it.
+	diffs = diffValue(diffs, "dm_permission", desired.DMPermission == nil || *desired.DMPermission, deployed.DMPermission == nil || *deployed.DMPermission)
+	diffs = diffValue(diffs, "nsfw", boolValue(desired.NSFW), boolValue(deployed.NSFW))
+	return diffOptions(diffs, "options", desired.Options, deployed.Options)
+}
+
+// diffOptions describes the differences between two lists of options, matching options by name.
+func diffOptions(diffs []string, path string, desired []discord.ApplicationCommandOption, deployed []discord.ApplicationCommandOption) []string {
+	deployedByName := make(map[string]*discord.ApplicationCommandOption, len(deployed))
+	for i := range deployed {
+		deployedByName[deployed[i].Name] = &deployed[i]
+	}
+
+	desiredNames := make(map[string]bool, len(desired))
+	for i := range desired {
+		option := &desired[i]
+		optionPath := path + "." + option.Name
+		desiredNames[option.Name] = true
+
+		current, ok := deployedByName[option.Name]
+		if !ok {
+			diffs = append(diffs, optionPath+": added")
+			continue
+		}
+
+		diffs = diffValue(diffs, optionPath+".type", option.Type, current.Type)
+		diffs = diffValue(diffs, optionPath+".description", option.Description, current.Description)
+		diffs = diffValue(diffs, optionPath+".required", boolValue(option.Required), boolValue(current.Required))
+		diffs = diffValue(diffs, optionPath+".autocomplete", boolValue(option.Autocomplete), boolValue(current.Autocomplete))
+		diffs = diffValue(diffs, optionPath+".choices", formatChoices(option.Choices), formatChoices(current.Choices))
+		diffs = diffValue(diffs, optionPath+".channel_types", fmt.Sprint(option.ChannelTypes), fmt.Sprint(current.ChannelTypes))
+		diffs = diffValue(diffs, optionPath+".min_value", floatValue(option.MinValue), floatValue(current.MinValue))
+		diffs = diffValue(diffs, optionPath+".max_value", floatValue(option.MaxValue), floatValue(current.MaxValue))
+		diffs = diffValue(diffs, optionPath+".min_length", intValue(option.MinLength), intValue(current.MinLength))
+		diffs = diffValue(diffs, optionPath+".max_length", intValue(option.MaxLength), intValue(current.MaxLength))
+		diffs = diffValue(diffs, optionPath+".name_localizations", formatLocalizations(option.NameLocalizations), formatLocalizations(current.NameLocalizations))
+		diffs = diffValue(diffs, optionPath+".description_localizations", formatLocalizations(option.DescriptionLocalizations), formatLocalizations(current.DescriptionLocalizations))
+		diffs = diffOptions(diffs, optionPath+".options", option.Options, current.Options)
+	}
+
+	for _, option := range deployed {
+		if !desiredNames[option.Name] {
+			diffs = append(diffs, path+"."+option.Name+": removed")
+		}
+	}
+
+	if len(diffs) == 0 && !sameOrder(desired, deployed) {
+		diffs = append(diffs, path+": order changed")
+	}
+
+	return diffs
+}
+
+func diffValue(diffs []string, path string, desired interface{}, deployed interface{}) []string {
+	if desired == deployed {
+		return diffs
+	}
+	return append(diffs, fmt.Sprintf("%s: %#v -> %#v", path, deployed, desired))
+}
+
+func sameOrder(desired []discord.ApplicationCommandOption, deployed []discord.ApplicationCommandOption) bool {
+	if len(desired) != len(deployed) {
+		return false
+	}
+	for i := range desired {
+		if desired[i].Name != deployed[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+func formatChoices(choices []discord.ApplicationCommandOptionChoice) string {
+	formatted := make([]string, len(choices))
+	for i, choice := range choices {
+		formatted[i] = fmt.Sprintf("%s=%v", choice.Name, choice.Value)
+		if len(choice.NameLocalizations) > 0 {
+			formatted[i] += " (" + formatLocalizations(choice.NameLocalizations) + ")"
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// formatLocalizations formats localizations sorted by locale, so that they can be compared.
+func formatLocalizations(localizations map[string]string) string {
+	locales := make([]string, 0, len(localizations))
+	for locale := range localizations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	formatted := make([]string, len(locales))
+	for i, locale := range locales {
+		formatted[i] = fmt.Sprintf("%s=%s", locale, localizations[locale])
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
+
+// permissionsValue returns the names of the permissions, or nil if they aren't set.
+func permissionsValue(p *discord.Permissions) interface{} {
+	if p == nil {
+		return nil
+	}
+	return strings.Join(p.Names(), "|")
+}
+
+// floatValue returns the value of f, or nil if it isn't set.
+func floatValue(f *float64) interface{} {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
+
+// intValue returns the value of i, or nil if it isn't set.
+func intValue(i *int) interface{} {
+	if i == nil {
+		return nil
+	}
+	return *i
+}
+
+// fetchPlan compares the deployed global and guild commands with those in the spec.
+func fetchPlan(ctx context.Context, client *discord.Client, applicationId string, spec *config.Commands) ([]*scopePlan, error) {
+	deployed, err := client.ApplicationCommands.List(ctx, applicationId, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list application commands: %w", err)
+	}
+
+	plans := []*scopePlan{{Changes: computePlan(spec.Global, deployed)}}
+
+	guildIds := make([]string, 0, len(spec.Guilds))
+	for guildId := range spec.Guilds {
+		guildIds = append(guildIds, guildId)
+	}
+	sort.Strings(guildIds)
+
+	for _, guildId := range guildIds {
+		deployed, err := client.ApplicationCommands.ListGuild(ctx, applicationId, guildId, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list application commands in guild %s: %w", guildId, err)
+		}
+
+		plans = append(plans, &scopePlan{GuildId: guildId, Changes: computePlan(spec.Guilds[guildId], deployed)})
+	}
+
+	return plans, nil
+}
+
+// printPlan writes the plans in a readable form, returning whether there are any changes.
+func printPlan(w io.Writer, plans []*scopePlan) bool {
+	drift := false
+	for _, p := range plans {
+		fmt.Fprintf(w, "%s:\n", p)
+		if len(p.Changes) == 0 {
+			fmt.Fprintln(w, "  no changes")
+			continue
+		}
+
+		drift = true
+		for _, c := range p.Changes {
+			symbol := map[action]string{actionCreate: "+", actionEdit: "~", actionDelete: "-"}[c.Action]
+			fmt.Fprintf(w, "  %s %s %s\n", symbol, c.Action, c.Name)
+			for _, diff := range c.Diffs {
+				fmt.Fprintf(w, "      %s\n", diff)
+			}
+		}
+	}
+	return drift
+}
+
+// applyChange makes the API call for a single change.
+// Edits replace the whole command, so that fields removed from the spec are cleared.
+func applyChange(ctx context.Context, client *discord.Client, applicationId string, guildId string, c change) error {
+	commands := client.ApplicationCommands
+	var err error
+	switch {
+	case c.Action == actionCreate && guildId == "":
+		_, err = commands.Register(ctx, applicationId, c.Desired)
+	case c.Action == actionCreate:
+		_, err = commands.RegisterGuild(ctx, applicationId, guildId, c.Desired)
+	case c.Action == actionEdit && guildId == "":
+		_, err = commands.Replace(ctx, applicationId, c.Deployed.Id, c.Desired)
+	case c.Action == actionEdit:
+		_, err = commands.ReplaceGuild(ctx, applicationId, guildId, c.Deployed.Id, c.Desired)
+	case c.Action == actionDelete && guildId == "":
+		err = commands.Delete(ctx, applicationId, c.Deployed.Id)
+	case c.Action == actionDelete:
+		err = commands.DeleteGuild(ctx, applicationId, guildId, c.Deployed.Id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s %s: %w", c.Action, c.Name, err)
+	}
+	return nil
+}
+
+// plan prints the changes needed to bring the deployed commands in line with the spec,
+// returning errDrift if there are any.
+func plan(ctx context.Context, client *discord.Client, applicationId string, args []string) error {
+	flags := flag.NewFlagSet("plan", flag.ExitOnError)
+	specPath := flags.String("spec", defaultSpecPath, "path to the application commands spec file")
+	flags.Parse(args)
+
+	spec, err := config.Load(*specPath)
+	if err != nil {
+		return err
+	}
+
+	plans, err := fetchPlan(ctx, client, applicationId, spec)
+	if err != nil {
+		return err
+	}
+
+	if printPlan(os.Stdout, plans) {
+		return errDrift
+	}
+	return nil
+}
+
+// apply makes only the calls needed to bring the deployed commands in line with the spec.
+// With -dry-run it behaves like plan.
+func apply(ctx context.Context, client *discord.Client, applicationId string, args []string) error {
+	flags := flag.NewFlagSet("apply", flag.ExitOnError)
+	specPath := flags.String("spec", defaultSpecPath, "path to the application commands spec file")
+	dryRun := flags.Bool("dry-run", false, "print the changes without making them, exiting with status 2 if there are any")
+	flags.Parse(args)
+
+	spec, err := config.Load(*specPath)
+	if err != nil {
+		return err
+	}
+
+	return syncCommands(ctx, client, applicationId, spec, *dryRun)
+}
+
+// syncCommands prints and makes the calls needed to bring the deployed commands in line with spec.
+// If dryRun is true, no calls are made and errDrift is returned if there are any changes.
+func syncCommands(ctx context.Context, client *discord.Client, applicationId string, spec *config.Commands, dryRun bool) error {
+	plans, err := fetchPlan(ctx, client, applicationId, spec)
+	if err != nil {
+		return err
+	}
+
+	drift := printPlan(os.Stdout, plans)
+	if dryRun {
+		if drift {
+			return errDrift
+		}
+		return nil
+	}
+
+	for _, p := range plans {
+		for _, c := range p.Changes {
+			err := applyChange(ctx, client, applicationId, p.GuildId, c)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
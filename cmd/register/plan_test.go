@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func TestComputePlan(t *testing.T) {
+	deployed := []*discord.ApplicationCommand{
+		{
+			Id:          "1",
+			Type:        discord.ApplicationCommandTypeChatInput,
+			Name:        "left-pad",
+			Description: "Left-pad a message",
+			Options: []discord.ApplicationCommandOption{
+				{Name: "length", Description: "The length to pad to.", Type: discord.ApplicationCommandOptionTypeInteger},
+				{Name: "message", Description: "The message to pad.", Type: discord.ApplicationCommandOptionTypeString},
+			},
+		},
+		{
+			Id:           "2",
+			Type:         discord.ApplicationCommandTypeChatInput,
+			Name:         "version",
+			Description:  "Show the version",
+			DMPermission: discord.Bool(true),
+		},
+		{
+			Id:   "3",
+			Type: discord.ApplicationCommandTypeMessage,
+			Name: "Checkem this message",
+		},
+		{
+			Id:          "4",
+			Type:        discord.ApplicationCommandTypeChatInput,
+			Name:        "old",
+			Description: "An old command",
+		},
+	}
+
+	desired := []*discord.RegisterApplicationCommandOptions{
+		{
+			Name:        "left-pad",
+			Description: discord.String("Left-pad a message"),
+			Options: []discord.ApplicationCommandOption{
+				{Name: "length", Description: "The length to pad to.", Type: discord.ApplicationCommandOptionTypeInteger, Required: discord.Bool(true)},
+				{Name: "character", Description: "The character to pad with.", Type: discord.ApplicationCommandOptionTypeString},
+			},
+		},
+		{
+			Name:                     "version",
+			Type:                     discord.Int(discord.ApplicationCommandTypeChatInput),
+			Description:              discord.String("Show the version"),
+			DefaultMemberPermissions: discord.NewPermissions(discord.PermissionAdministrator),
+			DMPermission:             discord.Bool(false),
+		},
+		{
+			Name: "Checkem this message",
+			Type: discord.Int(discord.ApplicationCommandTypeMessage),
+		},
+		{
+			Name:        "new",
+			Description: discord.String("A new command"),
+		},
+	}
+
+	changes := computePlan(desired, deployed)
+
+	type summary struct {
+		Action action
+		Name   string
+		Diffs  []string
+	}
+	got := make([]summary, len(changes))
+	for i, c := range changes {
+		got[i] = summary{c.Action, c.Name, c.Diffs}
+	}
+
+	expected := []summary{
+		{
+			Action: actionEdit,
+			Name:   "left-pad",
+			Diffs: []string{
+				"options.length.required: false -> true",
+				"options.character: added",
+				"options.message: removed",
+			},
+		},
+		{
+			Action: actionEdit,
+			Name:   "version",
+			Diffs: []string{
+				`default_member_permissions: <nil> -> "ADMINISTRATOR"`,
+				"dm_permission: true -> false",
+			},
+		},
+		{Action: actionCreate, Name: "new"},
+		{Action: actionDelete, Name: "old"},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected changes %+v, got %+v", expected, got)
+	}
+}
+
+func TestDiffOptionsOrder(t *testing.T) {
+	a := discord.ApplicationCommandOption{Name: "a", Description: "A", Type: discord.ApplicationCommandOptionTypeString}
+	b := discord.ApplicationCommandOption{Name: "b", Description: "B", Type: discord.ApplicationCommandOptionTypeString}
+
+	diffs := diffOptions(nil, "options", []discord.ApplicationCommandOption{b, a}, []discord.ApplicationCommandOption{a, b})
+	if !reflect.DeepEqual(diffs, []string{"options: order changed"}) {
+		t.Errorf("expected the order change to be reported, got %v", diffs)
+	}
+}
+
+func TestDiffOptionsLimits(t *testing.T) {
+	deployed := discord.ApplicationCommandOption{Name: "length", Description: "Length", Type: discord.ApplicationCommandOptionTypeInteger}
+	desired := deployed
+	desired.MinValue = discord.Float(0)
+	desired.MaxValue = discord.Float(2000)
+
+	diffs := diffOptions(nil, "options", []discord.ApplicationCommandOption{desired}, []discord.ApplicationCommandOption{deployed})
+	expected := []string{"options.length.min_value: <nil> -> 0", "options.length.max_value: <nil> -> 2000"}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("expected diffs %q, got %q", expected, diffs)
+	}
+
+	diffs = diffOptions(nil, "options", []discord.ApplicationCommandOption{desired}, []discord.ApplicationCommandOption{desired})
+	if len(diffs) != 0 {
+		t.Errorf("expected no diffs, got %q", diffs)
+	}
+}
+
+func TestPrintPlan(t *testing.T) {
+	tt := []struct {
+		name  string
+		plans []*scopePlan
+		drift bool
+		want  string
+	}{
+		{
+			name:  "no changes",
+			plans: []*scopePlan{{}},
+			drift: false,
+			want:  "global:\n  no changes\n",
+		},
+		{
+			name: "changes",
+			plans: []*scopePlan{
+				{},
+				{
+					GuildId: "1234",
+					Changes: []change{
+						{Action: actionEdit, Name: "test", Diffs: []string{`description: "a" -> "b"`}},
+					},
+				},
+			},
+			drift: true,
+			want:  "global:\n  no changes\nguild 1234:\n  ~ edit test\n      description: \"a\" -> \"b\"\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			drift := printPlan(&buf, tc.plans)
+			if drift != tc.drift {
+				t.Errorf("expected drift %t, got %t", tc.drift, drift)
+			}
+
+			if got := buf.String(); got != tc.want {
+				t.Errorf("expected output:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyChange(t *testing.T) {
+	tt := []struct {
+		name     string
+		guildId  string
+		change   change
+		wantPath string
+		wantBody map[string]interface{}
+	}{
+		{
+			name: "edit clears unset fields",
+			change: change{
+				Action:   actionEdit,
+				Name:     "version",
+				Desired:  &discord.RegisterApplicationCommandOptions{Name: "version", Description: discord.String("Show the version")},
+				Deployed: &discord.ApplicationCommand{Id: "2", Name: "version"},
+			},
+			wantPath: "/applications/app/commands/2",
+			wantBody: map[string]interface{}{
+				"name":                       "version",
+				"name_localizations":         nil,
+				"description":                "Show the version",
+				"description_localizations":  nil,
+				"options":                    []interface{}{},
+				"default_member_permissions": nil,
+				"dm_permission":              true,
+				"nsfw":                       false,
+			},
+		},
+		{
+			name:    "edit in guild",
+			guildId: "1234",
+			change: change{
+				Action: actionEdit,
+				Name:   "test",
+				Desired: &discord.RegisterApplicationCommandOptions{
+					Name:                     "test",
+					Description:              discord.String("Test command."),
+					DefaultMemberPermissions: discord.NewPermissions(discord.PermissionAdministrator),
+					DMPermission:             discord.Bool(false),
+					NSFW:                     discord.Bool(true),
+				},
+				Deployed: &discord.ApplicationCommand{Id: "3", Name: "test"},
+			},
+			wantPath: "/applications/app/guilds/1234/commands/3",
+			wantBody: map[string]interface{}{
+				"name":                       "test",
+				"name_localizations":         nil,
+				"description":                "Test command.",
+				"description_localizations":  nil,
+				"options":                    []interface{}{},
+				"default_member_permissions": "8",
+				"dm_permission":              false,
+				"nsfw":                       true,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotMethod, gotPath string
+			var gotBody map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotMethod = r.Method
+				gotPath = r.URL.Path
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Error(err)
+				}
+				if err := json.Unmarshal(b, &gotBody); err != nil {
+					t.Errorf("failed to decode body %s: %s", b, err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"id":"1"}`))
+			}))
+			defer server.Close()
+
+			client := discord.NewClient("token", discord.WithBaseURL(server.URL))
+			err := applyChange(context.Background(), client, "app", tc.guildId, tc.change)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if gotMethod != http.MethodPatch {
+				t.Errorf("expected method %s, got %s", http.MethodPatch, gotMethod)
+			}
+			if gotPath != tc.wantPath {
+				t.Errorf("expected path %s, got %s", tc.wantPath, gotPath)
+			}
+			if !reflect.DeepEqual(gotBody, tc.wantBody) {
+				t.Errorf("expected body %v, got %v", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
@@ -1,18 +1,60 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
 
+	"github.com/brattonross/ghostedbot/config"
+	"github.com/brattonross/ghostedbot/internal/bot"
 	"github.com/brattonross/ghostedbot/internal/discord"
 )
 
-type commands struct {
-	Global []*discord.RegisterApplicationCommandOptions `json:"global,omitempty"`
+const defaultSpecPath = "./config/application_commands.json"
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  register [spec]            overwrite the deployed commands with those in the spec file
+  register list [flags]      list the deployed commands
+  register plan [flags]      show how the deployed commands differ from the spec file, exiting with status 2 if they do
+  register apply [flags]     create, edit and delete commands so that they match the spec file
+  register validate [spec]   check the spec file against the bot's registered handlers
+  register dump [flags]      print the commands defined by the bot's handlers in the spec file format
+  register push [flags]      create, edit and delete global commands so that they match those defined by the bot's handlers
+
+The spec file defaults to %s.
+`, defaultSpecPath)
 }
 
 func main() {
+	args := os.Args[1:]
+	subcommand := ""
+	if len(args) > 0 {
+		subcommand = args[0]
+	}
+
+	switch subcommand {
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+	case "validate":
+		// validating doesn't talk to Discord, so it doesn't need any credentials.
+		err := validate(args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "dump":
+		err := dump(args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	applicationId := os.Getenv("DISCORD_APPLICATION_ID")
 	botToken := os.Getenv("DISCORD_BOT_TOKEN")
 
@@ -24,36 +66,89 @@ func main() {
 		log.Fatal("Missing required environment variable DISCORD_BOT_TOKEN")
 	}
 
-	applicationCommandsSpecPath := "./config/application_commands.json"
-	args := os.Args[1:]
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := discord.NewClient(botToken)
+
+	var err error
+	switch subcommand {
+	case "list":
+		err = list(ctx, client, applicationId, args[1:])
+	case "plan":
+		err = plan(ctx, client, applicationId, args[1:])
+	case "apply":
+		err = apply(ctx, client, applicationId, args[1:])
+	case "push":
+		err = push(ctx, client, applicationId, args[1:])
+	default:
+		err = overwrite(ctx, client, applicationId, args)
+	}
+	if errors.Is(err, errDrift) {
+		stop()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// validate checks the commands in the spec file against the handlers registered by the bot.
+func validate(args []string) error {
+	applicationCommandsSpecPath := defaultSpecPath
 	if len(args) > 0 {
 		applicationCommandsSpecPath = args[0]
 	}
 
-	jsonFile, err := os.Open(applicationCommandsSpecPath)
+	commands, err := config.Load(applicationCommandsSpecPath)
 	if err != nil {
-		log.Fatalf("failed to open commands.json: %s\n", err)
+		return err
 	}
 
-	defer jsonFile.Close()
-
-	var commands commands
-	err = json.NewDecoder(jsonFile).Decode(&commands)
+	err = bot.NewHandler(nil).ValidateCommands(commands.All())
 	if err != nil {
-		log.Fatalf("failed to decode commands.json: %s\n", err)
+		return fmt.Errorf("application commands do not match the registered handlers:\n%w", err)
 	}
 
-	client := discord.NewClient(botToken)
+	log.Println("application commands match the registered handlers")
+	return nil
+}
+
+// overwrite replaces the deployed commands with those in the spec file.
+func overwrite(ctx context.Context, client *discord.Client, applicationId string, args []string) error {
+	applicationCommandsSpecPath := defaultSpecPath
+	if len(args) > 0 {
+		applicationCommandsSpecPath = args[0]
+	}
 
-	registeredCommands, err := client.ApplicationCommands.BulkOverwrite(applicationId, commands.Global)
+	commands, err := config.Load(applicationCommandsSpecPath)
 	if err != nil {
-		log.Fatalf("failed to bulk overwrite application commands: %s\n", err)
+		return err
 	}
 
-	commandNames := make([]string, len(registeredCommands))
-	for i, command := range registeredCommands {
-		commandNames[i] = command.Name
+	registeredCommands, err := client.ApplicationCommands.BulkOverwrite(ctx, applicationId, commands.Global)
+	if err != nil {
+		return fmt.Errorf("failed to bulk overwrite application commands: %w", err)
+	}
+
+	log.Printf("registered application commands: %v\n", commandNames(registeredCommands))
+
+	for guildId, guildCommands := range commands.Guilds {
+		registeredCommands, err := client.ApplicationCommands.BulkOverwriteGuild(ctx, applicationId, guildId, guildCommands)
+		if err != nil {
+			return fmt.Errorf("failed to bulk overwrite application commands in guild %s: %w", guildId, err)
+		}
+
+		log.Printf("registered application commands in guild %s: %v\n", guildId, commandNames(registeredCommands))
 	}
 
-	log.Printf("registered application commands: %v\n", commandNames)
+	return nil
+}
+
+func commandNames(commands []*discord.ApplicationCommand) []string {
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = command.Name
+	}
+	return names
 }
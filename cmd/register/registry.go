@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/brattonross/ghostedbot/config"
+	"github.com/brattonross/ghostedbot/internal/bot"
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+// registryCommands returns the commands defined by the bot's handlers, as a spec of global commands.
+// It returns an error if Discord would refuse any of them.
+func registryCommands() (*config.Commands, error) {
+	commands := &config.Commands{Global: bot.NewHandler(nil).Commands()}
+	err := commands.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid application commands:\n%w", err)
+	}
+	return commands, nil
+}
+
+// dump prints the commands defined by the bot's handlers in the spec file format.
+func dump(args []string) error {
+	flags := flag.NewFlagSet("dump", flag.ExitOnError)
+	output := flags.String("o", "", "write the spec to this file instead of stdout")
+	flags.Parse(args)
+
+	commands, err := registryCommands()
+	if err != nil {
+		return err
+	}
+
+	w := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	return enc.Encode(commands)
+}
+
+// push makes only the calls needed to bring the deployed global commands in line with those defined by the bot's handlers.
+func push(ctx context.Context, client *discord.Client, applicationId string, args []string) error {
+	flags := flag.NewFlagSet("push", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "print the changes without making them, exiting with status 2 if there are any")
+	flags.Parse(args)
+
+	commands, err := registryCommands()
+	if err != nil {
+		return err
+	}
+
+	return syncCommands(ctx, client, applicationId, commands, *dryRun)
+}
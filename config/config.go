@@ -0,0 +1,146 @@
+// Package config holds the application commands spec, which describes the commands registered with Discord.
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+//go:embed application_commands.json
+var applicationCommands []byte
+
+// Commands describes the global and guild commands to register with Discord.
+type Commands struct {
+	Global []*discord.RegisterApplicationCommandOptions `json:"global,omitempty"`
+	// Guilds maps guild IDs to the commands that are only available in that guild.
+	Guilds map[string][]*discord.RegisterApplicationCommandOptions `json:"guilds,omitempty"`
+}
+
+// All returns the global commands followed by the commands of every guild.
+func (c *Commands) All() []*discord.RegisterApplicationCommandOptions {
+	all := append([]*discord.RegisterApplicationCommandOptions{}, c.Global...)
+	for _, commands := range c.Guilds {
+		all = append(all, commands...)
+	}
+	return all
+}
+
+// Validate checks every command against the rules that Discord enforces when registering commands.
+func (c *Commands) Validate() error {
+	var errs []error
+	for _, command := range c.Global {
+		if err := command.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("global: %w", err))
+		}
+	}
+	for guildId, commands := range c.Guilds {
+		for _, command := range commands {
+			if err := command.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("guild %s: %w", guildId, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// specCommand is a command as written in the spec file,
+// where default_member_permissions may also be given as permission names, e.g. "MANAGE_GUILD|KICK_MEMBERS".
+type specCommand struct {
+	*discord.RegisterApplicationCommandOptions
+	DefaultMemberPermissions *specPermissions `json:"default_member_permissions,omitempty"`
+}
+
+type specPermissions discord.Permissions
+
+func (p *specPermissions) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return (*discord.Permissions)(p).UnmarshalJSON(data)
+	}
+
+	permissions, err := discord.ParsePermissions(s)
+	if err != nil {
+		return err
+	}
+	*p = specPermissions(permissions)
+	return nil
+}
+
+func (c *specCommand) command() *discord.RegisterApplicationCommandOptions {
+	command := c.RegisterApplicationCommandOptions
+	if command == nil {
+		command = &discord.RegisterApplicationCommandOptions{}
+	}
+	if c.DefaultMemberPermissions != nil {
+		command.DefaultMemberPermissions = discord.NewPermissions(discord.Permissions(*c.DefaultMemberPermissions))
+	}
+	return command
+}
+
+func specCommands(commands []*specCommand) []*discord.RegisterApplicationCommandOptions {
+	if commands == nil {
+		return nil
+	}
+
+	converted := make([]*discord.RegisterApplicationCommandOptions, len(commands))
+	for i, command := range commands {
+		converted[i] = command.command()
+	}
+	return converted
+}
+
+// Decode reads and validates a commands spec.
+func Decode(r io.Reader) (*Commands, error) {
+	var spec struct {
+		Global []*specCommand            `json:"global"`
+		Guilds map[string][]*specCommand `json:"guilds"`
+	}
+	err := json.NewDecoder(r).Decode(&spec)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := Commands{Global: specCommands(spec.Global)}
+	if spec.Guilds != nil {
+		commands.Guilds = make(map[string][]*discord.RegisterApplicationCommandOptions, len(spec.Guilds))
+		for guildId, guildCommands := range spec.Guilds {
+			commands.Guilds[guildId] = specCommands(guildCommands)
+		}
+	}
+
+	err = commands.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid application commands:\n%w", err)
+	}
+
+	return &commands, nil
+}
+
+// Load reads the commands spec at path.
+func Load(path string) (*Commands, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	commands, err := Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	return commands, nil
+}
+
+// ApplicationCommands returns the commands spec that was embedded when the bot was built.
+func ApplicationCommands() (*Commands, error) {
+	return Decode(bytes.NewReader(applicationCommands))
+}
@@ -0,0 +1,38 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/config"
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func TestDecodePermissionNames(t *testing.T) {
+	commands, err := config.Decode(strings.NewReader(`{
+		"global": [{"name": "ban", "description": "Bans a member", "default_member_permissions": "BAN_MEMBERS|KICK_MEMBERS", "dm_permission": false}],
+		"guilds": {"1234": [{"name": "test", "description": "Test command", "default_member_permissions": "8"}]}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ban := commands.Global[0]
+	want := discord.PermissionBanMembers | discord.PermissionKickMembers
+	if ban.DefaultMemberPermissions == nil || *ban.DefaultMemberPermissions != want {
+		t.Errorf("expected default member permissions %s, got %v", want, ban.DefaultMemberPermissions)
+	}
+	if ban.Name != "ban" || ban.DMPermission == nil || *ban.DMPermission {
+		t.Errorf("expected the rest of the command to be decoded, got %+v", ban)
+	}
+
+	test := commands.Guilds["1234"][0]
+	if test.DefaultMemberPermissions == nil || *test.DefaultMemberPermissions != discord.PermissionAdministrator {
+		t.Errorf("expected default member permissions %s, got %v", discord.PermissionAdministrator, test.DefaultMemberPermissions)
+	}
+
+	_, err = config.Decode(strings.NewReader(`{"global": [{"name": "ban", "description": "Bans a member", "default_member_permissions": "BAN"}]}`))
+	if err == nil {
+		t.Error("expected an unknown permission name to be rejected")
+	}
+}
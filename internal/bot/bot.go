@@ -0,0 +1,58 @@
+// Package bot registers the handlers for all of the bot's commands and interactions.
+package bot
+
+import (
+	"fmt"
+
+	"github.com/brattonross/ghostedbot/internal/checkem"
+	"github.com/brattonross/ghostedbot/internal/debug"
+	"github.com/brattonross/ghostedbot/internal/discord"
+	"github.com/brattonross/ghostedbot/internal/mdn"
+	"github.com/brattonross/ghostedbot/internal/words"
+	"github.com/brattonross/ghostedbot/internal/year/progress"
+)
+
+var testCommand = &discord.Command{
+	Name:                     "test",
+	Description:              "Test command.",
+	DefaultMemberPermissions: discord.NewPermissions(discord.PermissionAdministrator),
+	DMPermission:             discord.Bool(false),
+	Handler: func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		return discord.MessageResponse("test successful <:AlienUnpleased:940285855292080149>"), nil
+	},
+	HandlerOptions: []discord.HandlerOption{discord.WithDefaultFlags(discord.MessageFlagEphemeral)},
+}
+
+var versionCommand = &discord.Command{
+	Name:                     "version",
+	Description:              "Print version information.",
+	DefaultMemberPermissions: discord.NewPermissions(discord.PermissionAdministrator),
+	DMPermission:             discord.Bool(false),
+	Handler: func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		return discord.MessageResponse(fmt.Sprintf("Built %s using commit %s", debug.FormattedBuildDate(), debug.BuildHash)), nil
+	},
+	HandlerOptions: []discord.HandlerOption{discord.WithDefaultFlags(discord.MessageFlagEphemeral)},
+}
+
+// NewHandler creates an interactions handler with all of the bot's handlers registered.
+func NewHandler(publicKey []byte) *discord.InteractionsHandler {
+	handler := discord.NewInteractionsHandler(publicKey)
+	handler.Use(discord.Recover(), discord.LogTiming(), discord.MapErrors(nil))
+
+	handler.RegisterCommand(checkem.Command)
+	handler.RegisterCommand(words.LeftPadCommand)
+	handler.RegisterCommand(mdn.Command)
+	handler.RegisterCommand(words.ShuffleCommand)
+	handler.RegisterCommand(words.TextCommand)
+	handler.RegisterCommand(testCommand)
+	handler.RegisterCommand(versionCommand)
+	handler.RegisterCommand(progress.Command)
+	handler.RegisterCommand(checkem.MessageCommand)
+	handler.RegisterCommand(words.ShuffleMessageCommand)
+
+	handler.RegisterModalHandler(words.LeftPadModalCustomId, words.LeftPadModalHandler)
+	handler.RegisterModalHandler(words.ShuffleModalCustomId, words.ShuffleModalHandler)
+	handler.RegisterComponentHandler(words.ReshuffleCustomId, words.ReshuffleHandler)
+
+	return handler
+}
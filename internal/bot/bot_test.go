@@ -0,0 +1,43 @@
+package bot_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/config"
+	"github.com/brattonross/ghostedbot/internal/bot"
+)
+
+func TestHandlersMatchApplicationCommands(t *testing.T) {
+	commands, err := config.ApplicationCommands()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = bot.NewHandler(nil).ValidateCommands(commands.All())
+	if err != nil {
+		t.Errorf("expected the application commands to match the registered handlers, got:\n%s", err)
+	}
+}
+
+func TestApplicationCommandsMatchRegistry(t *testing.T) {
+	commands, err := config.ApplicationCommands()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := json.Marshal(commands.Global)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(bot.NewHandler(nil).Commands())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("expected config/application_commands.json to match the registered commands, run `go run ./cmd/register dump -o config/application_commands.json` to update it")
+	}
+}
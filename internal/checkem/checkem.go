@@ -48,3 +48,22 @@ func Checkem(id string) string {
 func Handler(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
 	return discord.MessageResponse(Checkem(ctx.Interaction.Id)), nil
 }
+
+// MessageHandler is a discord message command handler that checks the ID of the targeted message.
+func MessageHandler(ctx *discord.InteractionContext, message *discord.Message) (*discord.InteractionResponse, error) {
+	return discord.MessageResponse(Checkem(message.Id)), nil
+}
+
+// Command is the checkem application command.
+var Command = &discord.Command{
+	Name:        "checkem",
+	Description: "Posts the ID of your message and checks for dubs or better.",
+	Handler:     Handler,
+}
+
+// MessageCommand is the message context menu command that checks the ID of the targeted message.
+var MessageCommand = &discord.Command{
+	Type:           discord.ApplicationCommandTypeMessage,
+	Name:           "Checkem this message",
+	MessageHandler: MessageHandler,
+}
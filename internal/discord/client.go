@@ -0,0 +1,85 @@
+package discord
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultBaseURL = "https://discord.com/api/v10/"
+
+// defaultUserAgent identifies the bot to Discord, in the format Discord requires.
+const defaultUserAgent = "DiscordBot (https://github.com/brattonross/ghostedbot, 1.0)"
+
+type service struct {
+	client *Client
+}
+
+type Client struct {
+	botToken    string
+	client      *http.Client
+	rateLimiter *rateLimiter
+	userAgent   string
+
+	BaseURL *url.URL
+
+	// Reuse a single common service instead of one for each section of the API.
+	common service
+
+	ApplicationCommands *ApplicationCommandsClient
+	InteractionWebhooks *InteractionWebhooksClient
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithTransport sets the transport used to send requests, such as one that records or stubs them.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.client.Transport = transport
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithBaseURL sets the URL that API paths are resolved against, such as the URL of a local fake server.
+// Invalid URLs are ignored.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		if !strings.HasSuffix(baseURL, "/") {
+			// paths are resolved relative to the base URL, so it must end with a slash.
+			baseURL += "/"
+		}
+
+		u, err := url.Parse(baseURL)
+		if err == nil {
+			c.BaseURL = u
+		}
+	}
+}
+
+func NewClient(botToken string, opts ...ClientOption) *Client {
+	baseURL, _ := url.Parse(defaultBaseURL)
+
+	c := &Client{
+		client:      &http.Client{},
+		rateLimiter: newRateLimiter(),
+		userAgent:   defaultUserAgent,
+		BaseURL:     baseURL,
+		botToken:    botToken,
+	}
+	c.common.client = c
+	c.ApplicationCommands = (*ApplicationCommandsClient)(&c.common)
+	c.InteractionWebhooks = (*InteractionWebhooksClient)(&c.common)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
@@ -0,0 +1,157 @@
+package discord
+
+import "fmt"
+
+// Command defines an application command together with the handlers that respond to it.
+// It is registered with an InteractionsHandler by RegisterCommand,
+// and its definition for Discord is given by RegisterOptions.
+type Command struct {
+	// Type defaults to ApplicationCommandTypeChatInput.
+	Type                     int
+	Name                     string
+	NameLocalizations        map[string]string
+	Description              string
+	DescriptionLocalizations map[string]string
+	// Options are the options of a command or subcommand without subcommands.
+	Options []ApplicationCommandOption
+
+	// DefaultMemberPermissions, DMPermission and NSFW control who can use a top-level command, and where.
+	// They are ignored on subcommands. See RegisterApplicationCommandOptions.
+	DefaultMemberPermissions *Permissions
+	DMPermission             *bool
+	NSFW                     *bool
+
+	// Handler handles chat input commands and subcommands.
+	// A handler on a command with subcommands handles any subcommand without its own handler.
+	Handler ApplicationCommandHandlerFunc
+	// UserHandler handles user context menu commands.
+	UserHandler UserCommandHandlerFunc
+	// MessageHandler handles message context menu commands.
+	MessageHandler MessageCommandHandlerFunc
+	// HandlerOptions are applied to the command's handler.
+	HandlerOptions []HandlerOption
+	// Autocomplete maps option names to the handlers that provide their suggestions.
+	Autocomplete map[string]AutocompleteHandlerFunc
+
+	// Subcommands are the subcommands of a chat input command.
+	// A subcommand that has subcommands of its own is a subcommand group.
+	Subcommands []*Command
+}
+
+func (c *Command) commandType() int {
+	if c.Type == 0 {
+		return ApplicationCommandTypeChatInput
+	}
+	return c.Type
+}
+
+// RegisterOptions returns the definition of the command to register with Discord.
+func (c *Command) RegisterOptions() *RegisterApplicationCommandOptions {
+	options := &RegisterApplicationCommandOptions{
+		Name:                     c.Name,
+		NameLocalizations:        c.NameLocalizations,
+		DescriptionLocalizations: c.DescriptionLocalizations,
+		Options:                  c.options(),
+		DefaultMemberPermissions: c.DefaultMemberPermissions,
+		DMPermission:             c.DMPermission,
+		NSFW:                     c.NSFW,
+	}
+	if c.Type != 0 && c.Type != ApplicationCommandTypeChatInput {
+		options.Type = Int(c.Type)
+	}
+	if c.Description != "" {
+		options.Description = String(c.Description)
+	}
+	return options
+}
+
+// options returns the command's options, or its subcommands as options.
+func (c *Command) options() []ApplicationCommandOption {
+	if len(c.Subcommands) == 0 {
+		return c.Options
+	}
+
+	options := make([]ApplicationCommandOption, len(c.Subcommands))
+	for i, subcommand := range c.Subcommands {
+		optionType := ApplicationCommandOptionTypeSubCommand
+		if len(subcommand.Subcommands) > 0 {
+			optionType = ApplicationCommandOptionTypeSubCommandGroup
+		}
+
+		options[i] = ApplicationCommandOption{
+			Name:                     subcommand.Name,
+			NameLocalizations:        subcommand.NameLocalizations,
+			Description:              subcommand.Description,
+			DescriptionLocalizations: subcommand.DescriptionLocalizations,
+			Type:                     optionType,
+			Options:                  subcommand.options(),
+		}
+	}
+	return options
+}
+
+// expectedOptions returns the options that the command's handler reads.
+func (c *Command) expectedOptions() []ExpectedOption {
+	expected := make([]ExpectedOption, len(c.Options))
+	for i, option := range c.Options {
+		expected[i] = ExpectedOption{
+			Name:     option.Name,
+			Type:     option.Type,
+			Required: option.Required != nil && *option.Required,
+		}
+	}
+	return expected
+}
+
+// RegisterCommand registers the handlers of the command and its subcommands.
+// The command's options are declared as the options its handlers read, as with WithOptions.
+// It panics if the command has no handler for its type.
+func (h *InteractionsHandler) RegisterCommand(command *Command) {
+	switch command.commandType() {
+	case ApplicationCommandTypeUser:
+		if command.UserHandler == nil {
+			panic(fmt.Sprintf("discord: user command %q has no UserHandler", command.Name))
+		}
+		h.RegisterUserCommandHandler(command.Name, command.UserHandler, command.HandlerOptions...)
+	case ApplicationCommandTypeMessage:
+		if command.MessageHandler == nil {
+			panic(fmt.Sprintf("discord: message command %q has no MessageHandler", command.Name))
+		}
+		h.RegisterMessageCommandHandler(command.Name, command.MessageHandler, command.HandlerOptions...)
+	default:
+		h.registerChatInputCommand(command.Name, command)
+	}
+
+	h.commands = append(h.commands, command)
+}
+
+func (h *InteractionsHandler) registerChatInputCommand(path string, command *Command) {
+	if command.Handler == nil && len(command.Subcommands) == 0 {
+		panic(fmt.Sprintf("discord: command %q has no Handler", path))
+	}
+
+	if command.Handler != nil {
+		opts := command.HandlerOptions
+		if len(command.Subcommands) == 0 {
+			opts = append([]HandlerOption{WithOptions(command.expectedOptions()...)}, opts...)
+		}
+		h.RegisterApplicationCommandHandler(path, command.Handler, opts...)
+	}
+
+	for option, handler := range command.Autocomplete {
+		h.RegisterAutocompleteHandler(path, option, handler)
+	}
+
+	for _, subcommand := range command.Subcommands {
+		h.registerChatInputCommand(path+"/"+subcommand.Name, subcommand)
+	}
+}
+
+// Commands returns the definitions of the commands registered with RegisterCommand, in the order they were registered.
+func (h *InteractionsHandler) Commands() []*RegisterApplicationCommandOptions {
+	commands := make([]*RegisterApplicationCommandOptions, len(h.commands))
+	for i, command := range h.commands {
+		commands[i] = command.RegisterOptions()
+	}
+	return commands
+}
@@ -0,0 +1,109 @@
+package discord_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func TestCommandRegisterOptions(t *testing.T) {
+	command := &discord.Command{
+		Name:                     "text",
+		Description:              "Text tools.",
+		NameLocalizations:        map[string]string{"fr": "texte"},
+		DefaultMemberPermissions: discord.NewPermissions(discord.PermissionManageMessages),
+		DMPermission:             discord.Bool(false),
+		Subcommands: []*discord.Command{
+			{
+				Name:        "shuffle",
+				Description: "Shuffles a message.",
+				Options: []discord.ApplicationCommandOption{
+					{Name: "message", Description: "The message to shuffle.", Type: discord.ApplicationCommandOptionTypeString},
+				},
+				Handler: noopHandler,
+			},
+			{
+				Name:        "tools",
+				Description: "More tools.",
+				Subcommands: []*discord.Command{
+					{Name: "reverse", Description: "Reverses a message.", Handler: noopHandler},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(command.RegisterOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"name":"text","name_localizations":{"fr":"texte"},"description":"Text tools.","options":[` +
+		`{"name":"shuffle","description":"Shuffles a message.","type":1,"options":[{"name":"message","description":"The message to shuffle.","type":3}]},` +
+		`{"name":"tools","description":"More tools.","type":2,"options":[{"name":"reverse","description":"Reverses a message.","type":1}]}],` +
+		`"default_member_permissions":"8192","dm_permission":false}`
+	if string(b) != want {
+		t.Errorf("expected %s, got %s", want, b)
+	}
+}
+
+func TestRegisterCommand(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	command := &discord.Command{
+		Name:        "text",
+		Description: "Text tools.",
+		Subcommands: []*discord.Command{
+			{
+				Name:        "shuffle",
+				Description: "Shuffles a message.",
+				Options: []discord.ApplicationCommandOption{
+					{Name: "message", Description: "The message to shuffle.", Type: discord.ApplicationCommandOptionTypeString, Required: discord.Bool(true), Autocomplete: discord.Bool(true)},
+				},
+				Handler: func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+					return discord.MessageResponse("shuffled"), nil
+				},
+				Autocomplete: map[string]discord.AutocompleteHandlerFunc{
+					"message": func(ctx *discord.InteractionContext, value string) ([]discord.ApplicationCommandOptionChoice, error) {
+						return nil, nil
+					},
+				},
+			},
+		},
+	}
+	handler.RegisterCommand(command)
+	handler.RegisterCommand(&discord.Command{
+		Type: discord.ApplicationCommandTypeMessage,
+		Name: "Shuffle this message",
+		MessageHandler: func(ctx *discord.InteractionContext, message *discord.Message) (*discord.InteractionResponse, error) {
+			return nil, nil
+		},
+	})
+
+	commands := handler.Commands()
+	if len(commands) != 2 || commands[0].Name != "text" || commands[1].Name != "Shuffle this message" {
+		t.Fatalf("expected the registered command definitions in order, got %v", commands)
+	}
+
+	err := handler.ValidateCommands(commands)
+	if err != nil {
+		t.Errorf("expected the command definitions to match the registered handlers, got %v", err)
+	}
+
+	w := serveCommand(t, handler, "text", discord.ApplicationCommandInteractionDataOption{Name: "shuffle", Type: discord.ApplicationCommandOptionTypeSubCommand})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response discord.InteractionResponse
+	err = json.NewDecoder(w.Body).Decode(&response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Data == nil || response.Data.Content == nil || *response.Data.Content != "shuffled" {
+		t.Errorf("expected the subcommand handler to respond")
+	}
+}
@@ -0,0 +1,282 @@
+package discord
+
+import (
+	"encoding/json"
+)
+
+const (
+	ComponentTypeActionRow         = 1
+	ComponentTypeButton            = 2
+	ComponentTypeStringSelect      = 3
+	ComponentTypeTextInput         = 4
+	ComponentTypeUserSelect        = 5
+	ComponentTypeRoleSelect        = 6
+	ComponentTypeMentionableSelect = 7
+	ComponentTypeChannelSelect     = 8
+)
+
+const (
+	ButtonStylePrimary   = 1
+	ButtonStyleSecondary = 2
+	ButtonStyleSuccess   = 3
+	ButtonStyleDanger    = 4
+	ButtonStyleLink      = 5
+)
+
+const (
+	TextInputStyleShort     = 1
+	TextInputStyleParagraph = 2
+)
+
+// Component is an interactive element that can be attached to a message.
+type Component interface {
+	ComponentType() int
+}
+
+// ActionRow is a container for other components.
+// Messages may have up to 5 action rows, each holding up to 5 buttons or a single select menu.
+type ActionRow struct {
+	Components []Component `json:"components"`
+}
+
+func (r ActionRow) ComponentType() int {
+	return ComponentTypeActionRow
+}
+
+func (r ActionRow) MarshalJSON() ([]byte, error) {
+	type alias ActionRow
+	return json.Marshal(struct {
+		Type int `json:"type"`
+		alias
+	}{
+		Type:  ComponentTypeActionRow,
+		alias: alias(r),
+	})
+}
+
+func (r *ActionRow) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Components []json.RawMessage `json:"components"`
+	}
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+
+	r.Components = make([]Component, len(raw.Components))
+	for i, data := range raw.Components {
+		r.Components[i], err = unmarshalComponent(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unmarshalComponent decodes a component into its concrete type, based on the type field.
+func unmarshalComponent(data []byte) (Component, error) {
+	var header struct {
+		Type int `json:"type"`
+	}
+	err := json.Unmarshal(data, &header)
+	if err != nil {
+		return nil, err
+	}
+
+	var component Component
+	switch header.Type {
+	case ComponentTypeActionRow:
+		component = &ActionRow{}
+	case ComponentTypeButton:
+		component = &Button{}
+	case ComponentTypeTextInput:
+		component = &TextInput{}
+	case ComponentTypeStringSelect, ComponentTypeUserSelect, ComponentTypeRoleSelect, ComponentTypeMentionableSelect, ComponentTypeChannelSelect:
+		component = &SelectMenu{}
+	default:
+		return &UnknownComponent{Type: header.Type, Raw: data}, nil
+	}
+
+	err = json.Unmarshal(data, component)
+	if err != nil {
+		return nil, err
+	}
+
+	return component, nil
+}
+
+// UnknownComponent holds a component of a type this package does not know about.
+type UnknownComponent struct {
+	Type int
+	Raw  json.RawMessage
+}
+
+func (c UnknownComponent) ComponentType() int {
+	return c.Type
+}
+
+func (c UnknownComponent) MarshalJSON() ([]byte, error) {
+	return c.Raw, nil
+}
+
+type Emoji struct {
+	Id       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Animated bool   `json:"animated,omitempty"`
+}
+
+type Button struct {
+	Style    int    `json:"style"`
+	Label    string `json:"label,omitempty"`
+	Emoji    *Emoji `json:"emoji,omitempty"`
+	CustomId string `json:"custom_id,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+func (b Button) ComponentType() int {
+	return ComponentTypeButton
+}
+
+func (b Button) MarshalJSON() ([]byte, error) {
+	type alias Button
+	return json.Marshal(struct {
+		Type int `json:"type"`
+		alias
+	}{
+		Type:  ComponentTypeButton,
+		alias: alias(b),
+	})
+}
+
+type SelectOption struct {
+	Label       string `json:"label"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Emoji       *Emoji `json:"emoji,omitempty"`
+	Default     bool   `json:"default,omitempty"`
+}
+
+// SelectMenu is any of the select menu components.
+// Type determines which kind of values the user can pick from.
+type SelectMenu struct {
+	Type         int            `json:"type"`
+	CustomId     string         `json:"custom_id"`
+	Options      []SelectOption `json:"options,omitempty"`
+	ChannelTypes []int          `json:"channel_types,omitempty"`
+	Placeholder  string         `json:"placeholder,omitempty"`
+	MinValues    *int           `json:"min_values,omitempty"`
+	MaxValues    *int           `json:"max_values,omitempty"`
+	Disabled     bool           `json:"disabled,omitempty"`
+}
+
+func (s SelectMenu) ComponentType() int {
+	return s.Type
+}
+
+// TextInput is a text field that can only be used in modals.
+// When a modal is submitted, Value holds the text entered by the user.
+type TextInput struct {
+	CustomId    string `json:"custom_id"`
+	Style       int    `json:"style,omitempty"`
+	Label       string `json:"label,omitempty"`
+	MinLength   *int   `json:"min_length,omitempty"`
+	MaxLength   *int   `json:"max_length,omitempty"`
+	Required    *bool  `json:"required,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Placeholder string `json:"placeholder,omitempty"`
+}
+
+func (t TextInput) ComponentType() int {
+	return ComponentTypeTextInput
+}
+
+func (t TextInput) MarshalJSON() ([]byte, error) {
+	type alias TextInput
+	return json.Marshal(struct {
+		Type int `json:"type"`
+		alias
+	}{
+		Type:  ComponentTypeTextInput,
+		alias: alias(t),
+	})
+}
+
+// NewActionRow creates an action row containing the given components.
+func NewActionRow(components ...Component) ActionRow {
+	return ActionRow{
+		Components: components,
+	}
+}
+
+// NewButton creates a button that sends an interaction with the given custom ID when clicked.
+func NewButton(style int, label string, customId string) *Button {
+	return &Button{
+		Style:    style,
+		Label:    label,
+		CustomId: customId,
+	}
+}
+
+// NewLinkButton creates a button that navigates to the given URL when clicked.
+// Link buttons do not send an interaction.
+func NewLinkButton(label string, url string) *Button {
+	return &Button{
+		Style: ButtonStyleLink,
+		Label: label,
+		URL:   url,
+	}
+}
+
+// NewStringSelectMenu creates a select menu for picking from the given options.
+func NewStringSelectMenu(customId string, options ...SelectOption) *SelectMenu {
+	return &SelectMenu{
+		Type:     ComponentTypeStringSelect,
+		CustomId: customId,
+		Options:  options,
+	}
+}
+
+// NewUserSelectMenu creates a select menu for picking users.
+func NewUserSelectMenu(customId string) *SelectMenu {
+	return &SelectMenu{
+		Type:     ComponentTypeUserSelect,
+		CustomId: customId,
+	}
+}
+
+// NewRoleSelectMenu creates a select menu for picking roles.
+func NewRoleSelectMenu(customId string) *SelectMenu {
+	return &SelectMenu{
+		Type:     ComponentTypeRoleSelect,
+		CustomId: customId,
+	}
+}
+
+// NewMentionableSelectMenu creates a select menu for picking users and roles.
+func NewMentionableSelectMenu(customId string) *SelectMenu {
+	return &SelectMenu{
+		Type:     ComponentTypeMentionableSelect,
+		CustomId: customId,
+	}
+}
+
+// NewChannelSelectMenu creates a select menu for picking channels.
+// If any channel types are given, only channels of those types can be picked.
+func NewChannelSelectMenu(customId string, channelTypes ...int) *SelectMenu {
+	return &SelectMenu{
+		Type:         ComponentTypeChannelSelect,
+		CustomId:     customId,
+		ChannelTypes: channelTypes,
+	}
+}
+
+// NewTextInput creates a text input for use in a modal.
+func NewTextInput(customId string, label string, style int) *TextInput {
+	return &TextInput{
+		CustomId: customId,
+		Style:    style,
+		Label:    label,
+	}
+}
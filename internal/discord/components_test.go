@@ -0,0 +1,203 @@
+package discord_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func TestActionRowRoundTrip(t *testing.T) {
+	row := discord.NewActionRow(
+		discord.NewButton(discord.ButtonStylePrimary, "Click", "click:1"),
+		discord.NewChannelSelectMenu("channel", 0),
+	)
+
+	b, err := json.Marshal(row)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded discord.ActionRow
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Components) != 2 {
+		t.Fatalf("expected %d components, got %d", 2, len(decoded.Components))
+	}
+
+	button, ok := decoded.Components[0].(*discord.Button)
+	if !ok {
+		t.Fatalf("expected first component to be a button, got %T", decoded.Components[0])
+	}
+
+	if button.CustomId != "click:1" {
+		t.Errorf("expected custom ID %s, got %s", "click:1", button.CustomId)
+	}
+
+	menu, ok := decoded.Components[1].(*discord.SelectMenu)
+	if !ok {
+		t.Fatalf("expected second component to be a select menu, got %T", decoded.Components[1])
+	}
+
+	if menu.ComponentType() != discord.ComponentTypeChannelSelect {
+		t.Errorf("expected component type %d, got %d", discord.ComponentTypeChannelSelect, menu.ComponentType())
+	}
+}
+
+func TestMessageComponentInteraction(t *testing.T) {
+	tt := []struct {
+		name     string
+		customId string
+		expected string
+	}{
+		{
+			name:     "matches prefix",
+			customId: "vote:yes",
+			expected: "vote",
+		},
+		{
+			name:     "prefers longest prefix",
+			customId: "vote:reset",
+			expected: "reset",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(map[string]interface{}{
+				"type": discord.InteractionTypeMessageComponent,
+				"data": map[string]interface{}{
+					"custom_id":      tc.customId,
+					"component_type": discord.ComponentTypeButton,
+				},
+				"message": map[string]interface{}{
+					"id":      "1",
+					"content": "original",
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+			w := httptest.NewRecorder()
+
+			handler := discord.NewInteractionsHandler(nil)
+			handler.Validator = &passingValidator{}
+
+			handler.RegisterComponentHandler("vote:", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+				if ctx.Interaction.Message.Content != "original" {
+					t.Errorf("expected message content %s, got %s", "original", ctx.Interaction.Message.Content)
+				}
+
+				return discord.UpdateMessageResponse(&discord.InteractionResponseData{Content: discord.String("vote")}), nil
+			})
+			handler.RegisterComponentHandler("vote:reset", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+				return discord.UpdateMessageResponse(&discord.InteractionResponseData{Content: discord.String("reset")}), nil
+			})
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected response status code %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var response discord.InteractionResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Type != discord.InteractionResponseTypeUpdateMessage {
+				t.Errorf("expected response type %d, got %d", discord.InteractionResponseTypeUpdateMessage, response.Type)
+			}
+
+			if *response.Data.Content != tc.expected {
+				t.Errorf("expected response content %s, got %s", tc.expected, *response.Data.Content)
+			}
+		})
+	}
+}
+
+func TestModalSubmitInteraction(t *testing.T) {
+	b, err := json.Marshal(map[string]interface{}{
+		"type": discord.InteractionTypeModalSubmit,
+		"data": map[string]interface{}{
+			"custom_id": "feedback",
+			"components": []interface{}{
+				discord.NewActionRow(&discord.TextInput{CustomId: "subject", Value: "Hello"}),
+				discord.NewActionRow(&discord.TextInput{CustomId: "body", Value: "line one\nline two"}),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	handler.RegisterModalHandler("feedback", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		data := ctx.Interaction.ModalSubmitData()
+
+		body, ok := data.TextInputValue("body")
+		if !ok {
+			t.Errorf("expected text input %s to be present", "body")
+		}
+
+		if _, ok := data.TextInputValue("missing"); ok {
+			t.Errorf("expected text input %s to be missing", "missing")
+		}
+
+		return discord.MessageResponse(body), nil
+	})
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected response status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response discord.InteractionResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if *response.Data.Content != "line one\nline two" {
+		t.Errorf("expected response content %q, got %q", "line one\nline two", *response.Data.Content)
+	}
+}
+
+func TestModalResponse(t *testing.T) {
+	res := discord.ModalResponse("feedback", "Feedback", discord.NewActionRow(discord.NewTextInput("body", "Body", discord.TextInputStyleParagraph)))
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded discord.InteractionResponse
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Type != discord.InteractionResponseTypeModal {
+		t.Errorf("expected response type %d, got %d", discord.InteractionResponseTypeModal, decoded.Type)
+	}
+
+	input, ok := decoded.Data.Components[0].Components[0].(*discord.TextInput)
+	if !ok {
+		t.Fatalf("expected a text input, got %T", decoded.Data.Components[0].Components[0])
+	}
+
+	if input.Style != discord.TextInputStyleParagraph {
+		t.Errorf("expected text input style %d, got %d", discord.TextInputStyleParagraph, input.Style)
+	}
+}
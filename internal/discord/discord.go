@@ -2,6 +2,7 @@ package discord
 
 import (
 	"bytes"
+	"context"
 	"crypto/ed25519"
 	"encoding/hex"
 	"encoding/json"
@@ -9,7 +10,9 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"net/url"
+	"runtime/debug"
+	"strings"
+	"time"
 )
 
 func String(v string) *string {
@@ -24,37 +27,156 @@ func Bool(v bool) *bool {
 	return &v
 }
 
+func Float(v float64) *float64 {
+	return &v
+}
+
 const (
-	InteractionTypePing               = 1
-	InteractionTypeApplicationCommand = 2
+	InteractionTypePing                           = 1
+	InteractionTypeApplicationCommand             = 2
+	InteractionTypeMessageComponent               = 3
+	InteractionTypeApplicationCommandAutocomplete = 4
+	InteractionTypeModalSubmit                    = 5
 )
 
+// InteractionData is the type-specific payload of an interaction.
+// It is one of *ApplicationCommandInteractionData, *MessageComponentInteractionData or *ModalSubmitInteractionData.
+type InteractionData interface {
+	interactionData()
+}
+
 type Interaction struct {
-	Id   string `json:"id"`
-	Type int    `json:"type"`
-	// TODO: We can only handle ping and application commands
-	// Maybe this should be interface{}, and then we can cast based on Type
-	Data ApplicationCommandInteractionData `json:"data,omitempty"`
+	Id            string          `json:"id"`
+	ApplicationId string          `json:"application_id"`
+	Type          int             `json:"type"`
+	Data          InteractionData `json:"data,omitempty"`
+	Guild         *Guild          `json:"guild,omitempty"`
+	GuildId       string          `json:"guild_id,omitempty"`
+	Channel       *Channel        `json:"channel,omitempty"`
+	ChannelId     string          `json:"channel_id,omitempty"`
+	// Member is the invoking member, when the interaction was invoked in a guild.
+	Member *Member `json:"member,omitempty"`
+	// User is the invoking user, when the interaction was invoked in a DM.
+	User    *User  `json:"user,omitempty"`
+	Token   string `json:"token"`
+	Version int    `json:"version"`
+	// Message is the message the component was attached to, for component interactions.
+	Message *Message `json:"message,omitempty"`
+	// AppPermissions is the set of permissions the app has in the channel the interaction was invoked in.
+	AppPermissions Permissions `json:"app_permissions,omitempty"`
+	// Locale is the selected language of the invoking user. It is not sent for ping interactions.
+	Locale string `json:"locale,omitempty"`
+	// GuildLocale is the preferred language of the guild the interaction was invoked in.
+	GuildLocale string `json:"guild_locale,omitempty"`
+}
+
+// String describes the interaction for logging by its ID, type and name.
+// It leaves out the token, which can be used to respond as the bot while it is valid.
+func (i Interaction) String() string {
+	return fmt.Sprintf("%s (type %d, %s)", i.Id, i.Type, interactionName(&i))
+}
+
+func (i *Interaction) UnmarshalJSON(b []byte) error {
+	type alias Interaction
+	raw := struct {
+		*alias
+		Data json.RawMessage `json:"data,omitempty"`
+	}{
+		alias: (*alias)(i),
+	}
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+
+	switch i.Type {
+	case InteractionTypeApplicationCommand, InteractionTypeApplicationCommandAutocomplete:
+		i.Data = &ApplicationCommandInteractionData{}
+	case InteractionTypeMessageComponent:
+		i.Data = &MessageComponentInteractionData{}
+	case InteractionTypeModalSubmit:
+		i.Data = &ModalSubmitInteractionData{}
+	default:
+		i.Data = nil
+		return nil
+	}
+
+	if len(raw.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(raw.Data, i.Data)
+}
+
+// ApplicationCommandData returns the data of an application command or autocomplete interaction.
+// An empty value is returned for other interaction types.
+func (i *Interaction) ApplicationCommandData() *ApplicationCommandInteractionData {
+	if data, ok := i.Data.(*ApplicationCommandInteractionData); ok {
+		return data
+	}
+	return &ApplicationCommandInteractionData{}
+}
+
+// MessageComponentData returns the data of a message component interaction.
+// An empty value is returned for other interaction types.
+func (i *Interaction) MessageComponentData() *MessageComponentInteractionData {
+	if data, ok := i.Data.(*MessageComponentInteractionData); ok {
+		return data
+	}
+	return &MessageComponentInteractionData{}
+}
+
+// ModalSubmitData returns the data of a modal submit interaction.
+// An empty value is returned for other interaction types.
+func (i *Interaction) ModalSubmitData() *ModalSubmitInteractionData {
+	if data, ok := i.Data.(*ModalSubmitInteractionData); ok {
+		return data
+	}
+	return &ModalSubmitInteractionData{}
+}
+
+// InvokingUser returns the user who invoked the interaction, whether it was invoked in a guild or a DM.
+func (i *Interaction) InvokingUser() *User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
 }
 
 const (
-	InteractionResponseTypePong                     = 1
-	InteractionResponseTypeChannelMessageWithSource = 4
+	InteractionResponseTypePong                                 = 1
+	InteractionResponseTypeChannelMessageWithSource             = 4
+	InteractionResponseTypeDeferredChannelMessageWithSource     = 5
+	InteractionResponseTypeDeferredUpdateMessage                = 6
+	InteractionResponseTypeUpdateMessage                        = 7
+	InteractionResponseTypeApplicationCommandAutocompleteResult = 8
+	InteractionResponseTypeModal                                = 9
 )
 
 type InteractionResponseData struct {
-	TTS             *bool         `json:"tts,omitempty"`
-	Content         *string       `json:"content,omitempty"`
-	Embeds          []interface{} `json:"embeds,omitempty"`
-	AllowedMentions *interface{}  `json:"allowed_mentions,omitempty"`
-	Flags           *int          `json:"flags,omitempty"`
-	Components      []interface{} `json:"components,omitempty"`
-	Attachments     []interface{} `json:"attachments,omitempty"`
+	TTS             *bool                `json:"tts,omitempty"`
+	Content         *string              `json:"content,omitempty"`
+	Embeds          []*Embed             `json:"embeds,omitempty"`
+	AllowedMentions *AllowedMentions     `json:"allowed_mentions,omitempty"`
+	Flags           MessageFlags         `json:"flags,omitempty"`
+	Components      []ActionRow          `json:"components,omitempty"`
+	Attachments     []*PartialAttachment `json:"attachments,omitempty"`
+	// Files are uploaded alongside the message. An attachment is added for any file without one.
+	Files []*File `json:"-"`
+	// Overflow determines what happens to content that is longer than MaxContentLength.
+	Overflow ContentOverflow `json:"-"`
+
+	// CustomId and Title are only used for modal responses.
+	CustomId *string `json:"custom_id,omitempty"`
+	Title    *string `json:"title,omitempty"`
 }
 
 type InteractionResponse struct {
 	Type int                      `json:"type,omitempty"`
 	Data *InteractionResponseData `json:"data,omitempty"`
+
+	// deferred is run in the background once a deferred response has been sent.
+	deferred DeferredHandlerFunc
 }
 
 // MessageResponse is a convenience function for creating a response that sends a basic text message.
@@ -67,6 +189,60 @@ func MessageResponse(message string) *InteractionResponse {
 	}
 }
 
+// EphemeralResponse is a convenience function for creating a response that sends a basic text message
+// which only the invoking user can see.
+func EphemeralResponse(message string) *InteractionResponse {
+	res := MessageResponse(message)
+	res.Data.Flags = MessageFlagEphemeral
+	return res
+}
+
+// DeferredHandlerFunc finishes handling an interaction after a deferred response has been sent.
+// The returned data is used to edit the original response.
+type DeferredHandlerFunc func(ctx *InteractionContext) (*InteractionResponseData, error)
+
+// DeferredMessageResponse is a convenience function for creating a response that acknowledges
+// the interaction straight away, showing a loading state to the user.
+// The provided function is then run in the background, and its result replaces the original response.
+func DeferredMessageResponse(fn DeferredHandlerFunc) *InteractionResponse {
+	return &InteractionResponse{
+		Type:     InteractionResponseTypeDeferredChannelMessageWithSource,
+		deferred: fn,
+	}
+}
+
+// UpdateMessageResponse is a convenience function for creating a response that
+// edits the message a component was attached to.
+func UpdateMessageResponse(data *InteractionResponseData) *InteractionResponse {
+	return &InteractionResponse{
+		Type: InteractionResponseTypeUpdateMessage,
+		Data: data,
+	}
+}
+
+// DeferredUpdateMessageResponse is a convenience function for creating a response that acknowledges
+// a component interaction without showing a loading state.
+// If fn is not nil it is run in the background, and its result replaces the message the component was attached to.
+func DeferredUpdateMessageResponse(fn DeferredHandlerFunc) *InteractionResponse {
+	return &InteractionResponse{
+		Type:     InteractionResponseTypeDeferredUpdateMessage,
+		deferred: fn,
+	}
+}
+
+// ModalResponse is a convenience function for creating a response that opens a modal dialog.
+// The components should be action rows containing a single text input each.
+func ModalResponse(customId string, title string, components ...ActionRow) *InteractionResponse {
+	return &InteractionResponse{
+		Type: InteractionResponseTypeModal,
+		Data: &InteractionResponseData{
+			CustomId:   String(customId),
+			Title:      String(title),
+			Components: components,
+		},
+	}
+}
+
 // InteractionsRequestValidator validates incoming requests to the interactions endpoint.
 type InteractionsRequestValidator interface {
 	// Validate returns an error if the request is not a valid interactions request.
@@ -74,66 +250,248 @@ type InteractionsRequestValidator interface {
 }
 
 type InteractionsHandler struct {
-	applicationCommands map[string]ApplicationCommandHandlerFunc
+	applicationCommands map[string]commandHandler
+	userCommands        map[string]commandHandler
+	messageCommands     map[string]commandHandler
+	autocompletes       map[autocompleteKey]AutocompleteHandlerFunc
+	components          []componentRoute
+	modals              map[string]ModalHandlerFunc
+	middleware          []Middleware
+	// expectedOptions holds the options declared with WithOptions, keyed by command path.
+	expectedOptions map[string][]ExpectedOption
+	// commands holds the commands registered with RegisterCommand.
+	commands []*Command
 
 	Validator InteractionsRequestValidator
+
+	// Client is used to edit responses and send follow-up messages for deferred interactions.
+	Client *Client
+
+	// AllowedMentions is used for any message that doesn't set its own allowed mentions.
+	// It defaults to NoMentions, so that user input echoed back by handlers can't ping anyone.
+	AllowedMentions *AllowedMentions
 }
 
 func (h *InteractionsHandler) handleUnhandledInteraction(w http.ResponseWriter, interaction *Interaction) {
-	log.Printf("unhandled interaction: %+v", interaction)
+	log.Printf("unhandled interaction: %s", interaction)
 	w.WriteHeader(http.StatusBadRequest)
 }
 
 func (h *InteractionsHandler) handlePingInteraction(w http.ResponseWriter, interaction *Interaction) {
-	log.Printf("handling ping interaction: %+v", interaction)
-	w.WriteHeader(http.StatusOK)
-	w.Header().Add("Content-Type", "application/json")
-
-	enc := json.NewEncoder(w)
-	enc.SetEscapeHTML(false)
-	err := enc.Encode(&InteractionResponse{
+	log.Printf("handling ping interaction: %s", interaction)
+	writeJSON(w, &InteractionResponse{
 		Type: InteractionResponseTypePong,
 	})
+}
+
+// writeJSON writes v as a JSON response.
+// The response is fully encoded before any headers are written, so that an encoding failure
+// can still be reported with the correct status code. It returns false if nothing was written.
+func writeJSON(w http.ResponseWriter, v interface{}) bool {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(v)
 	if err != nil {
 		log.Printf("failed to encode response: %s\n", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		// the status has already been sent, so all we can do is log.
+		log.Printf("failed to write response body: %s\n", err)
+	}
+
+	return true
+}
+
+// applicationCommandHandler returns the handler for the given command path.
+// If no handler is registered for a subcommand, the handler of its parent command or group is used.
+func (h *InteractionsHandler) applicationCommandHandler(path string) (commandHandler, bool) {
+	for {
+		handler, ok := h.applicationCommands[path]
+		if ok {
+			return handler, true
+		}
+
+		i := strings.LastIndex(path, "/")
+		if i < 0 {
+			return commandHandler{}, false
+		}
+		path = path[:i]
 	}
 }
 
-func (h *InteractionsHandler) handleApplicationCommandInteraction(w http.ResponseWriter, interaction *Interaction) {
-	handler, ok := h.applicationCommands[interaction.Data.Name]
+func (h *InteractionsHandler) handleApplicationCommandInteraction(w http.ResponseWriter, r *http.Request, interaction *Interaction) {
+	data := interaction.ApplicationCommandData()
+
+	var handler commandHandler
+	var ok bool
+	switch data.Type {
+	case ApplicationCommandTypeUser:
+		handler, ok = h.userCommands[data.Name]
+	case ApplicationCommandTypeMessage:
+		handler, ok = h.messageCommands[data.Name]
+	default:
+		handler, ok = h.applicationCommandHandler(data.CommandPath())
+	}
 	if !ok {
 		h.handleUnhandledInteraction(w, interaction)
 		return
 	}
 
-	log.Printf("handling application command: %+v", interaction)
-	ctx := &InteractionContext{
-		Interaction: interaction,
+	log.Printf("handling application command: %s", interaction)
+	h.respond(w, r, interaction, handler.fn, handler.defaultFlags)
+}
+
+func (h *InteractionsHandler) handleMessageComponentInteraction(w http.ResponseWriter, r *http.Request, interaction *Interaction) {
+	handler := h.componentHandler(interaction.MessageComponentData().CustomId)
+	if handler == nil {
+		h.handleUnhandledInteraction(w, interaction)
+		return
 	}
-	res, err := handler(ctx)
+
+	log.Printf("handling message component: %s", interaction)
+	h.respond(w, r, interaction, ApplicationCommandHandlerFunc(handler), 0)
+}
+
+func (h *InteractionsHandler) handleModalSubmitInteraction(w http.ResponseWriter, r *http.Request, interaction *Interaction) {
+	handler, ok := h.modals[interaction.ModalSubmitData().CustomId]
+	if !ok {
+		h.handleUnhandledInteraction(w, interaction)
+		return
+	}
+
+	log.Printf("handling modal submit: %s", interaction)
+	h.respond(w, r, interaction, ApplicationCommandHandlerFunc(handler), 0)
+}
+
+// respond runs the handler for an interaction and writes its response.
+// defaultFlags are the flags the handler was registered with, which middleware uses for responses it creates.
+func (h *InteractionsHandler) respond(w http.ResponseWriter, r *http.Request, interaction *Interaction, handler ApplicationCommandHandlerFunc, defaultFlags MessageFlags) {
+	ctx, cancel := h.newInteractionContext(r, interaction)
+	defer cancel()
+	ctx.defaultFlags = defaultFlags
+
+	res, err := h.wrap(handler)(ctx)
 	if err != nil {
-		log.Printf("failed to handle application command: %s\n", err)
+		log.Printf("failed to handle interaction: %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if res == nil {
+		log.Printf("failed to handle interaction: handler returned no response\n")
+		http.Error(w, "handler returned no response", http.StatusInternalServerError)
+		return
+	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Header().Add("Content-Type", "application/json; charset=utf-8")
+	var followups []*InteractionResponseData
+	switch res.Type {
+	case InteractionResponseTypeChannelMessageWithSource, InteractionResponseTypeUpdateMessage:
+		ctx.applyAllowedMentions(res.Data)
+		followups = res.Data.fitContent()
+	}
 
-	enc := json.NewEncoder(w)
-	enc.SetEscapeHTML(false)
-	err = enc.Encode(res)
+	if res.Data != nil && len(res.Data.Files) > 0 {
+		res.Data.attachFiles()
+		if !writeMultipart(w, res, res.Data.Files) {
+			return
+		}
+	} else if !writeJSON(w, res) {
+		return
+	}
+
+	// the background work below talks to Discord about this response, so make sure it has been sent first.
+	if res.deferred != nil || len(followups) > 0 {
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	if res.deferred != nil {
+		go h.runDeferred(ctx.detach(), res)
+	}
+	if len(followups) > 0 {
+		go h.sendFollowups(ctx.detach(), followups)
+	}
+}
+
+type autocompleteResponseData struct {
+	Choices []ApplicationCommandOptionChoice `json:"choices"`
+}
+
+type autocompleteResponse struct {
+	Type int                      `json:"type"`
+	Data autocompleteResponseData `json:"data"`
+}
+
+// maxAutocompleteChoices is the maximum number of choices Discord accepts in an autocomplete result.
+const maxAutocompleteChoices = 25
+
+func (h *InteractionsHandler) handleAutocompleteInteraction(w http.ResponseWriter, r *http.Request, interaction *Interaction) {
+	ctx, cancel := h.newInteractionContext(r, interaction)
+	defer cancel()
+
+	var focused *ApplicationCommandInteractionDataOption
+	options := ctx.Options()
+	for i := range options {
+		if options[i].Focused {
+			focused = &options[i]
+			break
+		}
+	}
+	if focused == nil {
+		h.handleUnhandledInteraction(w, interaction)
+		return
+	}
+
+	command := interaction.ApplicationCommandData().CommandPath()
+	handler, ok := h.autocompletes[autocompleteKey{command: command, option: focused.Name}]
+	if !ok {
+		h.handleUnhandledInteraction(w, interaction)
+		return
+	}
+
+	log.Printf("handling autocomplete: %s", interaction)
+
+	// the partial value is usually a string, but may be a number for numeric options.
+	value, ok := focused.Value.(string)
+	if !ok && focused.Value != nil {
+		value = fmt.Sprint(focused.Value)
+	}
+
+	choices, err := handler(ctx, value)
 	if err != nil {
-		log.Printf("failed to encode response: %s\n", err)
+		log.Printf("failed to handle autocomplete: %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(choices) > maxAutocompleteChoices {
+		choices = choices[:maxAutocompleteChoices]
+	}
+	if choices == nil {
+		// Discord expects an empty list rather than a missing field when there are no suggestions.
+		choices = []ApplicationCommandOptionChoice{}
 	}
+
+	writeJSON(w, &autocompleteResponse{
+		Type: InteractionResponseTypeApplicationCommandAutocompleteResult,
+		Data: autocompleteResponseData{
+			Choices: choices,
+		},
+	})
 }
 
 func (h *InteractionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
 		w.WriteHeader(http.StatusMethodNotAllowed)
-		w.Header().Add("Allow", http.MethodPost)
 		return
 	}
 
@@ -150,28 +508,394 @@ func (h *InteractionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	log.Printf("interaction received: %+v", interaction)
+	log.Printf("interaction received: %s", interaction)
 
 	switch {
 	case interaction.Type == InteractionTypePing:
 		h.handlePingInteraction(w, &interaction)
 		return
 	case interaction.Type == InteractionTypeApplicationCommand:
-		h.handleApplicationCommandInteraction(w, &interaction)
+		h.handleApplicationCommandInteraction(w, r, &interaction)
+		return
+	case interaction.Type == InteractionTypeMessageComponent:
+		h.handleMessageComponentInteraction(w, r, &interaction)
+		return
+	case interaction.Type == InteractionTypeApplicationCommandAutocomplete:
+		h.handleAutocompleteInteraction(w, r, &interaction)
+		return
+	case interaction.Type == InteractionTypeModalSubmit:
+		h.handleModalSubmitInteraction(w, r, &interaction)
 		return
 	}
 
 	h.handleUnhandledInteraction(w, &interaction)
 }
 
+// runDeferred runs the background part of a deferred response,
+// replacing the original response with the result.
+// Errors are shown only to the invoking user, using the message from MapErrors if it is in use.
+func (h *InteractionsHandler) runDeferred(ctx *InteractionContext, res *InteractionResponse) {
+	defer ctx.cancel()
+
+	data, err := callDeferred(ctx, res)
+	if err != nil {
+		log.Printf("failed to handle deferred interaction: %s\n", err)
+		errorMessage := ctx.errorMessage
+		if errorMessage == nil {
+			errorMessage = DefaultErrorMessage
+		}
+		data = &InteractionResponseData{
+			Content: String(errorMessage(err)),
+			Flags:   MessageFlagEphemeral,
+		}
+	}
+
+	// whether a message is ephemeral is fixed when the response is deferred, and editing can't change it.
+	// Ephemeral results of a public deferral are sent as an ephemeral follow-up instead.
+	deferredEphemeral := res.Data != nil && res.Data.Flags.Has(MessageFlagEphemeral)
+	if data != nil && data.Flags.Has(MessageFlagEphemeral) && !deferredEphemeral {
+		if res.Type == InteractionResponseTypeDeferredChannelMessageWithSource {
+			err = ctx.client.InteractionWebhooks.DeleteOriginalResponse(ctx.Context(), ctx.Interaction.ApplicationId, ctx.Interaction.Token)
+			if err != nil {
+				log.Printf("failed to delete original interaction response: %s\n", err)
+			}
+		}
+
+		_, err = ctx.CreateFollowupMessage(data)
+		if err != nil {
+			log.Printf("failed to send follow-up message: %s\n", err)
+		}
+		return
+	}
+
+	_, err = ctx.EditOriginalResponse(data)
+	if err != nil {
+		log.Printf("failed to edit original interaction response: %s\n", err)
+	}
+}
+
+// callDeferred calls the deferred function of a response, turning a panic into an error
+// so the user is shown an error message instead of being left with the deferral.
+func callDeferred(ctx *InteractionContext, res *InteractionResponse) (data *InteractionResponseData, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("panic while handling deferred %s: %v\n%s", interactionName(ctx.Interaction), r, debug.Stack())
+			data, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return res.deferred(ctx)
+}
+
+// sendFollowups sends the rest of a response whose content was split over several messages.
+func (h *InteractionsHandler) sendFollowups(ctx *InteractionContext, followups []*InteractionResponseData) {
+	defer ctx.cancel()
+
+	err := ctx.createFollowupMessages(followups)
+	if err != nil {
+		log.Printf("failed to send follow-up message: %s\n", err)
+	}
+}
+
+// ResponseWindow is how long Discord waits for the initial response to an interaction.
+const ResponseWindow = 3 * time.Second
+
+// InteractionTokenLifetime is how long the interaction token can be used to
+// edit the response and send follow-up messages.
+const InteractionTokenLifetime = 15 * time.Minute
+
 type InteractionContext struct {
 	Interaction *Interaction
+
+	client          *Client
+	ctx             context.Context
+	cancel          context.CancelFunc
+	allowedMentions *AllowedMentions
+	// defaultFlags are the default flags of the handler, set with WithDefaultFlags.
+	defaultFlags MessageFlags
+	// errorMessage turns an error into a message for the user. It is set by MapErrors.
+	errorMessage func(err error) string
+}
+
+// newInteractionContext creates the context passed to handlers.
+// Its context is cancelled when the request is, or when Discord stops waiting for a response.
+func (h *InteractionsHandler) newInteractionContext(r *http.Request, interaction *Interaction) (*InteractionContext, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(r.Context(), ResponseWindow)
+	return &InteractionContext{
+		Interaction:     interaction,
+		client:          h.Client,
+		ctx:             ctx,
+		cancel:          cancel,
+		allowedMentions: h.AllowedMentions,
+	}, cancel
+}
+
+// Context returns the context of the interaction.
+// Its deadline is the end of Discord's response window, except in deferred handlers
+// where it is the end of the interaction token's lifetime.
+// Outbound requests made while handling the interaction should use it.
+func (ctx *InteractionContext) Context() context.Context {
+	if ctx.ctx == nil {
+		return context.Background()
+	}
+	return ctx.ctx
+}
+
+// detach returns a copy of the context for handling the interaction in the background,
+// after the initial response has been sent and the request has finished.
+// The returned context's cancel function must be called once the background work is done.
+func (ctx *InteractionContext) detach() *InteractionContext {
+	c, cancel := context.WithTimeout(context.Background(), InteractionTokenLifetime)
+	return &InteractionContext{
+		Interaction:     ctx.Interaction,
+		client:          ctx.client,
+		ctx:             c,
+		cancel:          cancel,
+		allowedMentions: ctx.allowedMentions,
+		defaultFlags:    ctx.defaultFlags,
+		errorMessage:    ctx.errorMessage,
+	}
+}
+
+// applyAllowedMentions sets the default allowed mentions on data that doesn't set its own.
+func (ctx *InteractionContext) applyAllowedMentions(data *InteractionResponseData) {
+	if data != nil && data.AllowedMentions == nil {
+		data.AllowedMentions = ctx.allowedMentions
+	}
+}
+
+// EditOriginalResponse edits the initial response to the interaction.
+// If the content is split over several messages, the rest is sent as follow-up messages.
+func (ctx *InteractionContext) EditOriginalResponse(data *InteractionResponseData) (*Message, error) {
+	ctx.applyAllowedMentions(data)
+	followups := data.fitContent()
+
+	message, err := ctx.client.InteractionWebhooks.EditOriginalResponse(ctx.Context(), ctx.Interaction.ApplicationId, ctx.Interaction.Token, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, ctx.createFollowupMessages(followups)
+}
+
+// CreateFollowupMessage sends a new message in response to the interaction.
+// If the content is split over several messages, the first one is returned.
+func (ctx *InteractionContext) CreateFollowupMessage(data *InteractionResponseData) (*Message, error) {
+	ctx.applyAllowedMentions(data)
+	followups := data.fitContent()
+
+	message, err := ctx.client.InteractionWebhooks.CreateFollowupMessage(ctx.Context(), ctx.Interaction.ApplicationId, ctx.Interaction.Token, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, ctx.createFollowupMessages(followups)
+}
+
+// createFollowupMessages sends each of the given messages in order, stopping at the first error.
+func (ctx *InteractionContext) createFollowupMessages(followups []*InteractionResponseData) error {
+	for _, data := range followups {
+		_, err := ctx.client.InteractionWebhooks.CreateFollowupMessage(ctx.Context(), ctx.Interaction.ApplicationId, ctx.Interaction.Token, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type ApplicationCommandHandlerFunc func(ctx *InteractionContext) (*InteractionResponse, error)
 
-func (h *InteractionsHandler) RegisterApplicationCommandHandler(name string, handler ApplicationCommandHandlerFunc) {
-	h.applicationCommands[name] = handler
+// HandlerOption configures a handler when it is registered.
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	defaultFlags MessageFlags
+	options      []ExpectedOption
+}
+
+func newHandlerOptions(opts []HandlerOption) handlerOptions {
+	var options handlerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithDefaultFlags sets flags on every message the handler responds with, unless the response sets its own flags.
+// For example, WithDefaultFlags(MessageFlagEphemeral) makes all of a command's responses visible only to the invoking user.
+func WithDefaultFlags(flags MessageFlags) HandlerOption {
+	return func(o *handlerOptions) {
+		o.defaultFlags = flags
+	}
+}
+
+// commandHandler is a registered command handler, along with the default flags of its responses.
+type commandHandler struct {
+	fn           ApplicationCommandHandlerFunc
+	defaultFlags MessageFlags
+}
+
+func newCommandHandler(handler ApplicationCommandHandlerFunc, opts []HandlerOption) commandHandler {
+	return commandHandler{
+		fn:           applyHandlerOptions(handler, opts),
+		defaultFlags: newHandlerOptions(opts).defaultFlags,
+	}
+}
+
+// applyHandlerOptions wraps the handler to apply the given options to its responses.
+func applyHandlerOptions(handler ApplicationCommandHandlerFunc, opts []HandlerOption) ApplicationCommandHandlerFunc {
+	options := newHandlerOptions(opts)
+
+	if options.defaultFlags == 0 {
+		return handler
+	}
+
+	return func(ctx *InteractionContext) (*InteractionResponse, error) {
+		res, err := handler(ctx)
+		if err != nil || res == nil {
+			return res, err
+		}
+
+		switch res.Type {
+		case InteractionResponseTypeChannelMessageWithSource, InteractionResponseTypeDeferredChannelMessageWithSource:
+			if res.Data == nil {
+				res.Data = &InteractionResponseData{}
+			}
+			if res.Data.Flags == 0 {
+				res.Data.Flags = options.defaultFlags
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// RegisterApplicationCommandHandler registers a handler for the command with the given name.
+// Subcommands are addressed by their path, e.g. "text/left-pad" or "text/tools/shuffle".
+// A handler registered for a command also handles any of its subcommands that don't have their own handler.
+func (h *InteractionsHandler) RegisterApplicationCommandHandler(name string, handler ApplicationCommandHandlerFunc, opts ...HandlerOption) {
+	h.applicationCommands[name] = newCommandHandler(handler, opts)
+	if options := newHandlerOptions(opts).options; options != nil {
+		h.expectedOptions[name] = options
+	} else {
+		delete(h.expectedOptions, name)
+	}
+}
+
+// UserCommandHandlerFunc handles a user context menu command.
+// member is nil if the command was not invoked in a guild.
+type UserCommandHandlerFunc func(ctx *InteractionContext, user *User, member *Member) (*InteractionResponse, error)
+
+// RegisterUserCommandHandler registers a handler for the user context menu command with the given name.
+func (h *InteractionsHandler) RegisterUserCommandHandler(name string, handler UserCommandHandlerFunc, opts ...HandlerOption) {
+	h.userCommands[name] = newCommandHandler(func(ctx *InteractionContext) (*InteractionResponse, error) {
+		data := ctx.Interaction.ApplicationCommandData()
+		if data.Resolved == nil || data.Resolved.Users[data.TargetId] == nil {
+			return nil, fmt.Errorf("target user %s missing from resolved data", data.TargetId)
+		}
+
+		return handler(ctx, data.Resolved.Users[data.TargetId], data.Resolved.Members[data.TargetId])
+	}, opts)
+}
+
+// MessageCommandHandlerFunc handles a message context menu command.
+type MessageCommandHandlerFunc func(ctx *InteractionContext, message *Message) (*InteractionResponse, error)
+
+// RegisterMessageCommandHandler registers a handler for the message context menu command with the given name.
+func (h *InteractionsHandler) RegisterMessageCommandHandler(name string, handler MessageCommandHandlerFunc, opts ...HandlerOption) {
+	h.messageCommands[name] = newCommandHandler(func(ctx *InteractionContext) (*InteractionResponse, error) {
+		data := ctx.Interaction.ApplicationCommandData()
+		if data.Resolved == nil || data.Resolved.Messages[data.TargetId] == nil {
+			return nil, fmt.Errorf("target message %s missing from resolved data", data.TargetId)
+		}
+
+		return handler(ctx, data.Resolved.Messages[data.TargetId])
+	}, opts)
+}
+
+// CommandGroup registers handlers for the subcommands of a command or subcommand group.
+type CommandGroup struct {
+	handler *InteractionsHandler
+	path    string
+}
+
+// Group returns a CommandGroup for registering the subcommands of the named command.
+func (h *InteractionsHandler) Group(name string) *CommandGroup {
+	return &CommandGroup{
+		handler: h,
+		path:    name,
+	}
+}
+
+// Group returns a CommandGroup for registering the subcommands of the named subcommand group.
+func (g *CommandGroup) Group(name string) *CommandGroup {
+	return g.handler.Group(g.path + "/" + name)
+}
+
+// RegisterApplicationCommandHandler registers a handler for the named subcommand.
+func (g *CommandGroup) RegisterApplicationCommandHandler(name string, handler ApplicationCommandHandlerFunc, opts ...HandlerOption) {
+	g.handler.RegisterApplicationCommandHandler(g.path+"/"+name, handler, opts...)
+}
+
+// RegisterAutocompleteHandler registers an autocomplete handler for an option of the named subcommand.
+func (g *CommandGroup) RegisterAutocompleteHandler(name string, option string, handler AutocompleteHandlerFunc) {
+	g.handler.RegisterAutocompleteHandler(g.path+"/"+name, option, handler)
+}
+
+// AutocompleteHandlerFunc returns suggestions for the partial value of the focused option.
+// Only the first 25 choices are sent to Discord.
+type AutocompleteHandlerFunc func(ctx *InteractionContext, value string) ([]ApplicationCommandOptionChoice, error)
+
+type autocompleteKey struct {
+	command string
+	option  string
+}
+
+// RegisterAutocompleteHandler registers a handler that provides suggestions for the given option of a command.
+// Subcommands are addressed by their path, as with RegisterApplicationCommandHandler.
+// The option must be registered with Discord with autocomplete enabled.
+func (h *InteractionsHandler) RegisterAutocompleteHandler(command string, option string, handler AutocompleteHandlerFunc) {
+	h.autocompletes[autocompleteKey{command: command, option: option}] = handler
+}
+
+// ComponentHandlerFunc handles an interaction with a message component.
+type ComponentHandlerFunc func(ctx *InteractionContext) (*InteractionResponse, error)
+
+type componentRoute struct {
+	prefix  string
+	handler ComponentHandlerFunc
+}
+
+// RegisterComponentHandler registers a handler for message components whose custom ID starts with the given prefix.
+// This allows state to be encoded in the rest of the custom ID, e.g. "shuffle:1234".
+// When several prefixes match, the longest one wins.
+func (h *InteractionsHandler) RegisterComponentHandler(customIdPrefix string, handler ComponentHandlerFunc) {
+	h.components = append(h.components, componentRoute{prefix: customIdPrefix, handler: handler})
+}
+
+func (h *InteractionsHandler) componentHandler(customId string) ComponentHandlerFunc {
+	var match *componentRoute
+	for i, route := range h.components {
+		if !strings.HasPrefix(customId, route.prefix) {
+			continue
+		}
+		if match == nil || len(route.prefix) > len(match.prefix) {
+			match = &h.components[i]
+		}
+	}
+
+	if match == nil {
+		return nil
+	}
+	return match.handler
+}
+
+// ModalHandlerFunc handles the submission of a modal.
+type ModalHandlerFunc func(ctx *InteractionContext) (*InteractionResponse, error)
+
+// RegisterModalHandler registers a handler for submissions of the modal with the given custom ID.
+func (h *InteractionsHandler) RegisterModalHandler(customId string, handler ModalHandlerFunc) {
+	h.modals[customId] = handler
 }
 
 type ed25519Validator struct {
@@ -212,10 +936,18 @@ func (v *ed25519Validator) Validate(r *http.Request) error {
 // The provided public key is used to validate incoming requests.
 func NewInteractionsHandler(publicKey []byte) *InteractionsHandler {
 	return &InteractionsHandler{
-		applicationCommands: make(map[string]ApplicationCommandHandlerFunc),
+		applicationCommands: make(map[string]commandHandler),
+		userCommands:        make(map[string]commandHandler),
+		messageCommands:     make(map[string]commandHandler),
+		autocompletes:       make(map[autocompleteKey]AutocompleteHandlerFunc),
+		modals:              make(map[string]ModalHandlerFunc),
+		expectedOptions:     make(map[string][]ExpectedOption),
 		Validator: &ed25519Validator{
 			publicKey: publicKey,
 		},
+		// Interaction webhooks are authenticated by the interaction token, so no bot token is needed.
+		Client:          NewClient(""),
+		AllowedMentions: NoMentions(),
 	}
 }
 
@@ -226,17 +958,30 @@ const (
 )
 
 type ApplicationCommand struct {
-	Id            string  `json:"id"`
-	Type          int     `json:"type"`
-	ApplicationId string  `json:"application_id"`
-	GuildId       *string `json:"guild_id,omitempty"`
-	Name          string  `json:"name"`
-	Description   string  `json:"description"`
+	Id                       string                     `json:"id"`
+	Type                     int                        `json:"type"`
+	ApplicationId            string                     `json:"application_id"`
+	GuildId                  *string                    `json:"guild_id,omitempty"`
+	Name                     string                     `json:"name"`
+	NameLocalizations        map[string]string          `json:"name_localizations,omitempty"`
+	Description              string                     `json:"description"`
+	DescriptionLocalizations map[string]string          `json:"description_localizations,omitempty"`
+	Options                  []ApplicationCommandOption `json:"options,omitempty"`
+	// DefaultMemberPermissions is the set of permissions that members need to use the command by default.
+	DefaultMemberPermissions *Permissions `json:"default_member_permissions,omitempty"`
+	// DMPermission is whether a global command can be used in DMs with the bot.
+	DMPermission *bool `json:"dm_permission,omitempty"`
+	NSFW         *bool `json:"nsfw,omitempty"`
+	// Version is changed by Discord whenever the command is updated.
+	Version string `json:"version"`
 }
 
 type ApplicationCommandOptionChoice struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
+	Name              string            `json:"name"`
+	NameLocalizations map[string]string `json:"name_localizations,omitempty"`
+	// Value is a string, integer or number, matching the type of the option.
+	// Numbers decoded from JSON are always float64.
+	Value interface{} `json:"value"`
 }
 
 const (
@@ -259,19 +1004,106 @@ type ApplicationCommandOption struct {
 	Type        int                              `json:"type"`
 	Required    *bool                            `json:"required,omitempty"`
 	Choices     []ApplicationCommandOptionChoice `json:"choices,omitempty"`
+	// Autocomplete enables autocomplete interactions for this option.
+	// It may not be used alongside Choices.
+	Autocomplete *bool `json:"autocomplete,omitempty"`
+	// ChannelTypes restricts the channels that can be picked for a channel option.
+	ChannelTypes []int `json:"channel_types,omitempty"`
+	// MinValue and MaxValue limit the value of integer and number options.
+	MinValue *float64 `json:"min_value,omitempty"`
+	MaxValue *float64 `json:"max_value,omitempty"`
+	// MinLength and MaxLength limit the length of string options.
+	MinLength *int `json:"min_length,omitempty"`
+	MaxLength *int `json:"max_length,omitempty"`
+	// Options holds the options of a subcommand, or the subcommands of a subcommand group.
+	Options []ApplicationCommandOption `json:"options,omitempty"`
+	// NameLocalizations and DescriptionLocalizations map locales, such as "fr", to translations.
+	NameLocalizations        map[string]string `json:"name_localizations,omitempty"`
+	DescriptionLocalizations map[string]string `json:"description_localizations,omitempty"`
 }
 
 type RegisterApplicationCommandOptions struct {
-	Name        string                     `json:"name"`
-	Type        *int                       `json:"type,omitempty"`
-	Description *string                    `json:"description,omitempty"`
-	Options     []ApplicationCommandOption `json:"options,omitempty"`
+	Name                     string                     `json:"name"`
+	NameLocalizations        map[string]string          `json:"name_localizations,omitempty"`
+	Type                     *int                       `json:"type,omitempty"`
+	Description              *string                    `json:"description,omitempty"`
+	DescriptionLocalizations map[string]string          `json:"description_localizations,omitempty"`
+	Options                  []ApplicationCommandOption `json:"options,omitempty"`
+	// DefaultMemberPermissions is the set of permissions that members need to use the command by default.
+	// If it is nil, everyone can use the command. If it is empty, only administrators can.
+	DefaultMemberPermissions *Permissions `json:"default_member_permissions,omitempty"`
+	// DMPermission is whether a global command can be used in DMs with the bot. It defaults to true.
+	DMPermission *bool `json:"dm_permission,omitempty"`
+	// NSFW restricts the command to age-restricted channels.
+	NSFW *bool `json:"nsfw,omitempty"`
 }
 
 type ApplicationCommandInteractionDataOption struct {
 	Name  string      `json:"name"`
 	Type  int         `json:"type"`
 	Value interface{} `json:"value,omitempty"`
+	// Options holds the options of a subcommand or subcommand group.
+	Options []ApplicationCommandInteractionDataOption `json:"options,omitempty"`
+	// Focused is true for the option the user is currently typing in an autocomplete interaction.
+	Focused bool `json:"focused,omitempty"`
+}
+
+// ResolvedData holds the full objects for any entities referenced by options or targeted by context menu commands, keyed by ID.
+type ResolvedData struct {
+	Users map[string]*User `json:"users,omitempty"`
+	// Members are partial, and don't include the user. The user can be found in Users under the same ID.
+	Members     map[string]*Member     `json:"members,omitempty"`
+	Roles       map[string]*Role       `json:"roles,omitempty"`
+	Channels    map[string]*Channel    `json:"channels,omitempty"`
+	Messages    map[string]*Message    `json:"messages,omitempty"`
+	Attachments map[string]*Attachment `json:"attachments,omitempty"`
+}
+
+func (*ApplicationCommandInteractionData) interactionData() {}
+
+// CommandPath returns the name of the invoked command, followed by the names of any
+// invoked subcommand group and subcommand, separated by slashes, e.g. "text/left-pad".
+func (d *ApplicationCommandInteractionData) CommandPath() string {
+	path := d.Name
+	options := d.Options
+	for len(options) == 1 {
+		t := options[0].Type
+		if t != ApplicationCommandOptionTypeSubCommand && t != ApplicationCommandOptionTypeSubCommandGroup {
+			break
+		}
+		path += "/" + options[0].Name
+		options = options[0].Options
+	}
+	return path
+}
+
+type MessageComponentInteractionData struct {
+	CustomId      string `json:"custom_id"`
+	ComponentType int    `json:"component_type"`
+	// Values holds the selected values for select menu components.
+	Values []string `json:"values,omitempty"`
+}
+
+func (*MessageComponentInteractionData) interactionData() {}
+
+type ModalSubmitInteractionData struct {
+	CustomId   string      `json:"custom_id"`
+	Components []ActionRow `json:"components"`
+}
+
+func (*ModalSubmitInteractionData) interactionData() {}
+
+// TextInputValue returns the value submitted for the text input with the given custom ID.
+func (d *ModalSubmitInteractionData) TextInputValue(customId string) (string, bool) {
+	for _, row := range d.Components {
+		for _, component := range row.Components {
+			input, ok := component.(*TextInput)
+			if ok && input.CustomId == customId {
+				return input.Value, true
+			}
+		}
+	}
+	return "", false
 }
 
 type ApplicationCommandInteractionData struct {
@@ -279,53 +1111,53 @@ type ApplicationCommandInteractionData struct {
 	Name     string                                    `json:"name"`
 	Type     int                                       `json:"type"`
 	Options  []ApplicationCommandInteractionDataOption `json:"options,omitempty"`
+	Resolved *ResolvedData                             `json:"resolved,omitempty"`
 	GuildId  string                                    `json:"guild_id,omitempty"`
 	TargetId string                                    `json:"target_id,omitempty"`
 }
 
 type ApplicationCommandsClient service
 
-func (c *ApplicationCommandsClient) Register(applicationId string, options *RegisterApplicationCommandOptions) (*ApplicationCommand, error) {
-	u, err := c.client.BaseURL.Parse(fmt.Sprintf("applications/%s/commands", applicationId))
+// List returns the application's global commands.
+// If withLocalizations is true, the commands include all of their localized names and descriptions.
+func (c *ApplicationCommandsClient) List(ctx context.Context, applicationId string, withLocalizations bool) ([]*ApplicationCommand, error) {
+	var commands []*ApplicationCommand
+	err := c.client.do(ctx, http.MethodGet, fmt.Sprintf("applications/%s/commands?with_localizations=%t", applicationId, withLocalizations), nil, &commands)
 	if err != nil {
 		return nil, err
 	}
 
-	buf := &bytes.Buffer{}
-	if options != nil {
-		enc := json.NewEncoder(buf)
-		enc.SetEscapeHTML(false)
-		err = enc.Encode(options)
-		if err != nil {
-			return nil, err
-		}
-	}
+	return commands, nil
+}
 
-	req, err := http.NewRequest(http.MethodPost, u.String(), buf)
+// Get returns one of the application's global commands.
+func (c *ApplicationCommandsClient) Get(ctx context.Context, applicationId string, commandId string) (*ApplicationCommand, error) {
+	var command ApplicationCommand
+	err := c.client.do(ctx, http.MethodGet, fmt.Sprintf("applications/%s/commands/%s", applicationId, commandId), nil, &command)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bot %s", c.client.botToken))
+	return &command, nil
+}
 
-	res, err := c.client.client.Do(req)
+// Edit updates one of the application's global commands.
+// Only the fields that are set in options are changed.
+func (c *ApplicationCommandsClient) Edit(ctx context.Context, applicationId string, commandId string, options *RegisterApplicationCommandOptions) (*ApplicationCommand, error) {
+	var command ApplicationCommand
+	err := c.client.do(ctx, http.MethodPatch, fmt.Sprintf("applications/%s/commands/%s", applicationId, commandId), options, &command)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Check for 4xx or 5xx status codes
-	if res.StatusCode >= http.StatusBadRequest {
-		return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
-	}
+	return &command, nil
+}
 
+// Replace overwrites one of the application's global commands with options.
+// Unlike Edit, fields that are not set in options are reset to their defaults rather than left unchanged.
+func (c *ApplicationCommandsClient) Replace(ctx context.Context, applicationId string, commandId string, options *RegisterApplicationCommandOptions) (*ApplicationCommand, error) {
 	var command ApplicationCommand
-	err = json.NewDecoder(res.Body).Decode(&command)
-	if err == io.EOF {
-		// ignore EOF errors caused by empty response body
-		err = nil
-	}
+	err := c.client.do(ctx, http.MethodPatch, fmt.Sprintf("applications/%s/commands/%s", applicationId, commandId), newReplaceApplicationCommandBody(options), &command)
 	if err != nil {
 		return nil, err
 	}
@@ -333,80 +1165,135 @@ func (c *ApplicationCommandsClient) Register(applicationId string, options *Regi
 	return &command, nil
 }
 
-func (c *ApplicationCommandsClient) BulkOverwrite(applicationId string, commands []*RegisterApplicationCommandOptions) ([]*ApplicationCommand, error) {
-	u, err := c.client.BaseURL.Parse(fmt.Sprintf("applications/%s/commands", applicationId))
-	if err != nil {
-		return nil, err
+// replaceApplicationCommandBody is the body of a request to edit a command that sets every editable field,
+// sending null or the default value for those that are unset so that Discord clears them.
+type replaceApplicationCommandBody struct {
+	Name                     string                     `json:"name"`
+	NameLocalizations        map[string]string          `json:"name_localizations"`
+	Description              *string                    `json:"description,omitempty"`
+	DescriptionLocalizations map[string]string          `json:"description_localizations"`
+	Options                  []ApplicationCommandOption `json:"options"`
+	DefaultMemberPermissions *Permissions               `json:"default_member_permissions"`
+	DMPermission             bool                       `json:"dm_permission"`
+	NSFW                     bool                       `json:"nsfw"`
+}
+
+func newReplaceApplicationCommandBody(options *RegisterApplicationCommandOptions) *replaceApplicationCommandBody {
+	body := &replaceApplicationCommandBody{
+		Name:                     options.Name,
+		NameLocalizations:        options.NameLocalizations,
+		Description:              options.Description,
+		DescriptionLocalizations: options.DescriptionLocalizations,
+		Options:                  options.Options,
+		DefaultMemberPermissions: options.DefaultMemberPermissions,
+		DMPermission:             options.DMPermission == nil || *options.DMPermission,
+		NSFW:                     options.NSFW != nil && *options.NSFW,
+	}
+	if body.Options == nil {
+		body.Options = []ApplicationCommandOption{}
 	}
+	return body
+}
 
-	buf := &bytes.Buffer{}
-	enc := json.NewEncoder(buf)
-	enc.SetEscapeHTML(false)
-	err = enc.Encode(commands)
+// Delete deletes one of the application's global commands.
+func (c *ApplicationCommandsClient) Delete(ctx context.Context, applicationId string, commandId string) error {
+	return c.client.do(ctx, http.MethodDelete, fmt.Sprintf("applications/%s/commands/%s", applicationId, commandId), nil, nil)
+}
+
+// Register creates a global application command.
+// If a command with the same name already exists it is overwritten.
+func (c *ApplicationCommandsClient) Register(ctx context.Context, applicationId string, options *RegisterApplicationCommandOptions) (*ApplicationCommand, error) {
+	var command ApplicationCommand
+	err := c.client.do(ctx, http.MethodPost, fmt.Sprintf("applications/%s/commands", applicationId), options, &command)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPut, u.String(), buf)
+	return &command, nil
+}
+
+// BulkOverwrite replaces all of the application's global commands with the given commands.
+func (c *ApplicationCommandsClient) BulkOverwrite(ctx context.Context, applicationId string, commands []*RegisterApplicationCommandOptions) ([]*ApplicationCommand, error) {
+	var registered []*ApplicationCommand
+	err := c.client.do(ctx, http.MethodPut, fmt.Sprintf("applications/%s/commands", applicationId), commands, &registered)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bot %s", c.client.botToken))
+	return registered, nil
+}
 
-	res, err := c.client.client.Do(req)
+// RegisterGuild creates an application command that is only available in the given guild.
+// Unlike global commands, guild commands are available as soon as they are registered.
+func (c *ApplicationCommandsClient) RegisterGuild(ctx context.Context, applicationId string, guildId string, options *RegisterApplicationCommandOptions) (*ApplicationCommand, error) {
+	var command ApplicationCommand
+	err := c.client.do(ctx, http.MethodPost, fmt.Sprintf("applications/%s/guilds/%s/commands", applicationId, guildId), options, &command)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Check for 4xx or 5xx status codes
-	if res.StatusCode >= http.StatusBadRequest {
-		return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
-	}
+	return &command, nil
+}
 
-	var commandsResponse []*ApplicationCommand
-	err = json.NewDecoder(res.Body).Decode(&commandsResponse)
-	if err == io.EOF {
-		// ignore EOF errors caused by empty response body
-		err = nil
-	}
+// BulkOverwriteGuild replaces all of the application's commands in the given guild with the given commands.
+func (c *ApplicationCommandsClient) BulkOverwriteGuild(ctx context.Context, applicationId string, guildId string, commands []*RegisterApplicationCommandOptions) ([]*ApplicationCommand, error) {
+	var registered []*ApplicationCommand
+	err := c.client.do(ctx, http.MethodPut, fmt.Sprintf("applications/%s/guilds/%s/commands", applicationId, guildId), commands, &registered)
 	if err != nil {
 		return nil, err
 	}
 
-	return commandsResponse, nil
+	return registered, nil
 }
 
-const defaultBaseURL = "https://discord.com/api/v10/"
+// ListGuild returns the application's commands in the given guild.
+// If withLocalizations is true, the commands include all of their localized names and descriptions.
+func (c *ApplicationCommandsClient) ListGuild(ctx context.Context, applicationId string, guildId string, withLocalizations bool) ([]*ApplicationCommand, error) {
+	var commands []*ApplicationCommand
+	err := c.client.do(ctx, http.MethodGet, fmt.Sprintf("applications/%s/guilds/%s/commands?with_localizations=%t", applicationId, guildId, withLocalizations), nil, &commands)
+	if err != nil {
+		return nil, err
+	}
 
-type service struct {
-	client *Client
+	return commands, nil
 }
 
-type Client struct {
-	botToken string
-	client   *http.Client
+// GetGuild returns one of the application's commands in the given guild.
+func (c *ApplicationCommandsClient) GetGuild(ctx context.Context, applicationId string, guildId string, commandId string) (*ApplicationCommand, error) {
+	var command ApplicationCommand
+	err := c.client.do(ctx, http.MethodGet, fmt.Sprintf("applications/%s/guilds/%s/commands/%s", applicationId, guildId, commandId), nil, &command)
+	if err != nil {
+		return nil, err
+	}
 
-	BaseURL *url.URL
+	return &command, nil
+}
 
-	// Reuse a single common service instead of one for each section of the API.
-	common service
+// EditGuild updates one of the application's commands in the given guild.
+// Only the fields that are set in options are changed.
+func (c *ApplicationCommandsClient) EditGuild(ctx context.Context, applicationId string, guildId string, commandId string, options *RegisterApplicationCommandOptions) (*ApplicationCommand, error) {
+	var command ApplicationCommand
+	err := c.client.do(ctx, http.MethodPatch, fmt.Sprintf("applications/%s/guilds/%s/commands/%s", applicationId, guildId, commandId), options, &command)
+	if err != nil {
+		return nil, err
+	}
 
-	ApplicationCommands *ApplicationCommandsClient
+	return &command, nil
 }
 
-func NewClient(botToken string) *Client {
-	baseURL, _ := url.Parse(defaultBaseURL)
-
-	c := &Client{
-		client:   &http.Client{},
-		BaseURL:  baseURL,
-		botToken: botToken,
+// ReplaceGuild overwrites one of the application's commands in the given guild with options.
+// Unlike EditGuild, fields that are not set in options are reset to their defaults rather than left unchanged.
+func (c *ApplicationCommandsClient) ReplaceGuild(ctx context.Context, applicationId string, guildId string, commandId string, options *RegisterApplicationCommandOptions) (*ApplicationCommand, error) {
+	var command ApplicationCommand
+	err := c.client.do(ctx, http.MethodPatch, fmt.Sprintf("applications/%s/guilds/%s/commands/%s", applicationId, guildId, commandId), newReplaceApplicationCommandBody(options), &command)
+	if err != nil {
+		return nil, err
 	}
-	c.common.client = c
-	c.ApplicationCommands = (*ApplicationCommandsClient)(&c.common)
 
-	return c
+	return &command, nil
+}
+
+// DeleteGuild deletes one of the application's commands in the given guild.
+func (c *ApplicationCommandsClient) DeleteGuild(ctx context.Context, applicationId string, guildId string, commandId string) error {
+	return c.client.do(ctx, http.MethodDelete, fmt.Sprintf("applications/%s/guilds/%s/commands/%s", applicationId, guildId, commandId), nil, nil)
 }
@@ -2,12 +2,18 @@ package discord_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/brattonross/ghostedbot/internal/discord"
 )
@@ -117,7 +123,7 @@ func TestNewInteractionsHandler(t *testing.T) {
 	t.Run("Correctly handles valid application command interaction", func(t *testing.T) {
 		b, err := json.Marshal(&discord.Interaction{
 			Type: discord.InteractionTypeApplicationCommand,
-			Data: discord.ApplicationCommandInteractionData{
+			Data: &discord.ApplicationCommandInteractionData{
 				Id:   "1234567890",
 				Name: "blep",
 				Options: []discord.ApplicationCommandInteractionDataOption{
@@ -143,12 +149,12 @@ func TestNewInteractionsHandler(t *testing.T) {
 		handler.Validator = &passingValidator{}
 
 		handler.RegisterApplicationCommandHandler("blep", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
-			if ctx.Interaction.Data.Options[0].Value != "animal_dog" {
-				t.Errorf("expected option value %s, got %s", "animal_dog", ctx.Interaction.Data.Options[0].Value)
+			if ctx.Interaction.ApplicationCommandData().Options[0].Value != "animal_dog" {
+				t.Errorf("expected option value %s, got %s", "animal_dog", ctx.Interaction.ApplicationCommandData().Options[0].Value)
 			}
 
-			if ctx.Interaction.Data.Options[1].Value != "true" {
-				t.Errorf("expected option value %s, got %s", "true", ctx.Interaction.Data.Options[1].Value)
+			if ctx.Interaction.ApplicationCommandData().Options[1].Value != "true" {
+				t.Errorf("expected option value %s, got %s", "true", ctx.Interaction.ApplicationCommandData().Options[1].Value)
 			}
 
 			return &discord.InteractionResponse{
@@ -184,6 +190,243 @@ func TestNewInteractionsHandler(t *testing.T) {
 	})
 }
 
+func TestDeferredApplicationCommandInteraction(t *testing.T) {
+	edited := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("expected request method %s, got %s", http.MethodPatch, r.Method)
+		}
+
+		if r.URL.Path != "/webhooks/1234567890/token/messages/@original" {
+			t.Errorf("expected request path %s, got %s", "/webhooks/1234567890/token/messages/@original", r.URL.Path)
+		}
+
+		var data discord.InteractionResponseData
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			t.Error(err)
+		}
+
+		w.Write([]byte(`{"id": "1", "channel_id": "2", "content": "done"}`))
+		edited <- *data.Content
+	}))
+	defer server.Close()
+
+	b, err := json.Marshal(&discord.Interaction{
+		ApplicationId: "1234567890",
+		Type:          discord.InteractionTypeApplicationCommand,
+		Token:         "token",
+		Data: &discord.ApplicationCommandInteractionData{
+			Id:   "1234567890",
+			Name: "slow",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+	serverURL, _ := url.Parse(server.URL)
+	handler.Client.BaseURL = serverURL
+
+	handler.RegisterApplicationCommandHandler("slow", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		return discord.DeferredMessageResponse(func(ctx *discord.InteractionContext) (*discord.InteractionResponseData, error) {
+			return &discord.InteractionResponseData{
+				Content: discord.String("done"),
+			}, nil
+		}), nil
+	})
+
+	handler.ServeHTTP(w, req)
+
+	var response discord.InteractionResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Type != discord.InteractionResponseTypeDeferredChannelMessageWithSource {
+		t.Errorf("expected response type %d, got %d", discord.InteractionResponseTypeDeferredChannelMessageWithSource, response.Type)
+	}
+
+	select {
+	case content := <-edited:
+		if content != "done" {
+			t.Errorf("expected edited content %s, got %s", "done", content)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected original response to be edited")
+	}
+}
+
+func TestDeferredPanic(t *testing.T) {
+	type request struct {
+		method string
+		data   discord.InteractionResponseData
+	}
+	requests := make(chan request, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method}
+		if r.Method != http.MethodDelete {
+			if err := json.NewDecoder(r.Body).Decode(&req.data); err != nil {
+				t.Error(err)
+			}
+			w.Write([]byte(`{"id": "1"}`))
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		requests <- req
+	}))
+	defer server.Close()
+
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+	serverURL, _ := url.Parse(server.URL)
+	handler.Client.BaseURL = serverURL
+
+	handler.RegisterApplicationCommandHandler("panic", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		return discord.DeferredMessageResponse(func(ctx *discord.InteractionContext) (*discord.InteractionResponseData, error) {
+			panic("oops")
+		}), nil
+	})
+
+	w := serveCommand(t, handler, "panic")
+
+	var response discord.InteractionResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Type != discord.InteractionResponseTypeDeferredChannelMessageWithSource {
+		t.Fatalf("expected response type %d, got %d", discord.InteractionResponseTypeDeferredChannelMessageWithSource, response.Type)
+	}
+
+	for _, method := range []string{http.MethodDelete, http.MethodPost} {
+		select {
+		case req := <-requests:
+			if req.method != method {
+				t.Fatalf("expected request method %s, got %s", method, req.method)
+			}
+			if method != http.MethodPost {
+				continue
+			}
+
+			want := discord.DefaultErrorMessage(errors.New("oops"))
+			if req.data.Content == nil || *req.data.Content != want {
+				t.Errorf("expected follow-up content %q, got %v", want, req.data.Content)
+			}
+			if !req.data.Flags.Has(discord.MessageFlagEphemeral) {
+				t.Errorf("expected follow-up to be ephemeral, got flags %d", req.data.Flags)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected a %s request to Discord", method)
+		}
+	}
+}
+
+func TestAutocompleteInteraction(t *testing.T) {
+	b, err := json.Marshal(&discord.Interaction{
+		Type: discord.InteractionTypeApplicationCommandAutocomplete,
+		Data: &discord.ApplicationCommandInteractionData{
+			Id:   "1234567890",
+			Name: "blep",
+			Options: []discord.ApplicationCommandInteractionDataOption{
+				{
+					Name:  "size",
+					Type:  discord.ApplicationCommandOptionTypeString,
+					Value: "small",
+				},
+				{
+					Name:    "animal",
+					Type:    discord.ApplicationCommandOptionTypeString,
+					Value:   "do",
+					Focused: true,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	handler.RegisterAutocompleteHandler("blep", "animal", func(ctx *discord.InteractionContext, value string) ([]discord.ApplicationCommandOptionChoice, error) {
+		if value != "do" {
+			t.Errorf("expected focused value %s, got %s", "do", value)
+		}
+
+		choices := make([]discord.ApplicationCommandOptionChoice, 30)
+		for i := range choices {
+			choices[i] = discord.ApplicationCommandOptionChoice{Name: "Dog", Value: "animal_dog"}
+		}
+		return choices, nil
+	})
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected response status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Type int `json:"type"`
+		Data struct {
+			Choices []discord.ApplicationCommandOptionChoice `json:"choices"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Type != discord.InteractionResponseTypeApplicationCommandAutocompleteResult {
+		t.Errorf("expected response type %d, got %d", discord.InteractionResponseTypeApplicationCommandAutocompleteResult, response.Type)
+	}
+
+	if len(response.Data.Choices) != 25 {
+		t.Errorf("expected %d choices, got %d", 25, len(response.Data.Choices))
+	}
+}
+
+func TestCreateFollowupMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected request method %s, got %s", http.MethodPost, r.Method)
+		}
+
+		if r.URL.Path != "/webhooks/1234567890/token" {
+			t.Errorf("expected request path %s, got %s", "/webhooks/1234567890/token", r.URL.Path)
+		}
+
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("expected no Authorization header, got %s", r.Header.Get("Authorization"))
+		}
+
+		w.Write([]byte(`{"id": "1", "channel_id": "2", "content": "follow up"}`))
+	}))
+	defer server.Close()
+
+	client := discord.NewClient("")
+	serverURL, _ := url.Parse(server.URL)
+	client.BaseURL = serverURL
+
+	message, err := client.InteractionWebhooks.CreateFollowupMessage(context.Background(), "1234567890", "token", &discord.InteractionResponseData{
+		Content: discord.String("follow up"),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if message.Id != "1" {
+		t.Errorf("expected message ID %s, got %s", "1", message.Id)
+	}
+}
+
 func TestClientRegisterGlobalApplicationCommand(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -202,7 +445,7 @@ func TestClientRegisterGlobalApplicationCommand(t *testing.T) {
 	serverURL, _ := url.Parse(server.URL)
 	client.BaseURL = serverURL
 
-	command, err := client.ApplicationCommands.Register("1234567890", &discord.RegisterApplicationCommandOptions{
+	command, err := client.ApplicationCommands.Register(context.Background(), "1234567890", &discord.RegisterApplicationCommandOptions{
 		Name:        "blep",
 		Type:        discord.Int(discord.ApplicationCommandTypeChatInput),
 		Description: discord.String("Send a random adorable animal photo"),
@@ -262,7 +505,7 @@ func TestBulkOverwriteGlobalApplicationCommands(t *testing.T) {
 	serverURL, _ := url.Parse(server.URL)
 	client.BaseURL = serverURL
 
-	commands, err := client.ApplicationCommands.BulkOverwrite("1234567890", []*discord.RegisterApplicationCommandOptions{
+	commands, err := client.ApplicationCommands.BulkOverwrite(context.Background(), "1234567890", []*discord.RegisterApplicationCommandOptions{
 		{
 			Name:        "blep",
 			Type:        discord.Int(discord.ApplicationCommandTypeChatInput),
@@ -309,3 +552,604 @@ func TestBulkOverwriteGlobalApplicationCommands(t *testing.T) {
 		t.Errorf("expected command ID %s, got %s", "1234567890", commands[0].Id)
 	}
 }
+
+func TestApplicationCommandsCRUD(t *testing.T) {
+	options := &discord.RegisterApplicationCommandOptions{
+		Name:        "blep",
+		Description: discord.String("Send a random adorable animal photo"),
+	}
+
+	tt := []struct {
+		name   string
+		call   func(ctx context.Context, client *discord.Client) error
+		method string
+		path   string
+		query  string
+	}{
+		{
+			name: "list",
+			call: func(ctx context.Context, client *discord.Client) error {
+				_, err := client.ApplicationCommands.List(ctx, "1", false)
+				return err
+			},
+			method: http.MethodGet,
+			path:   "/applications/1/commands",
+			query:  "with_localizations=false",
+		},
+		{
+			name: "get",
+			call: func(ctx context.Context, client *discord.Client) error {
+				_, err := client.ApplicationCommands.Get(ctx, "1", "3")
+				return err
+			},
+			method: http.MethodGet,
+			path:   "/applications/1/commands/3",
+		},
+		{
+			name: "edit",
+			call: func(ctx context.Context, client *discord.Client) error {
+				_, err := client.ApplicationCommands.Edit(ctx, "1", "3", options)
+				return err
+			},
+			method: http.MethodPatch,
+			path:   "/applications/1/commands/3",
+		},
+		{
+			name: "delete",
+			call: func(ctx context.Context, client *discord.Client) error {
+				return client.ApplicationCommands.Delete(ctx, "1", "3")
+			},
+			method: http.MethodDelete,
+			path:   "/applications/1/commands/3",
+		},
+		{
+			name: "register guild",
+			call: func(ctx context.Context, client *discord.Client) error {
+				_, err := client.ApplicationCommands.RegisterGuild(ctx, "1", "2", options)
+				return err
+			},
+			method: http.MethodPost,
+			path:   "/applications/1/guilds/2/commands",
+		},
+		{
+			name: "bulk overwrite guild",
+			call: func(ctx context.Context, client *discord.Client) error {
+				_, err := client.ApplicationCommands.BulkOverwriteGuild(ctx, "1", "2", []*discord.RegisterApplicationCommandOptions{options})
+				return err
+			},
+			method: http.MethodPut,
+			path:   "/applications/1/guilds/2/commands",
+		},
+		{
+			name: "list guild",
+			call: func(ctx context.Context, client *discord.Client) error {
+				_, err := client.ApplicationCommands.ListGuild(ctx, "1", "2", true)
+				return err
+			},
+			method: http.MethodGet,
+			path:   "/applications/1/guilds/2/commands",
+			query:  "with_localizations=true",
+		},
+		{
+			name: "get guild",
+			call: func(ctx context.Context, client *discord.Client) error {
+				_, err := client.ApplicationCommands.GetGuild(ctx, "1", "2", "3")
+				return err
+			},
+			method: http.MethodGet,
+			path:   "/applications/1/guilds/2/commands/3",
+		},
+		{
+			name: "edit guild",
+			call: func(ctx context.Context, client *discord.Client) error {
+				_, err := client.ApplicationCommands.EditGuild(ctx, "1", "2", "3", options)
+				return err
+			},
+			method: http.MethodPatch,
+			path:   "/applications/1/guilds/2/commands/3",
+		},
+		{
+			name: "delete guild",
+			call: func(ctx context.Context, client *discord.Client) error {
+				return client.ApplicationCommands.DeleteGuild(ctx, "1", "2", "3")
+			},
+			method: http.MethodDelete,
+			path:   "/applications/1/guilds/2/commands/3",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tc.method {
+					t.Errorf("expected request method %s, got %s", tc.method, r.Method)
+				}
+
+				if r.URL.Path != tc.path {
+					t.Errorf("expected request path %s, got %s", tc.path, r.URL.Path)
+				}
+
+				if r.URL.RawQuery != tc.query {
+					t.Errorf("expected request query %q, got %q", tc.query, r.URL.RawQuery)
+				}
+
+				switch r.Method {
+				case http.MethodDelete:
+					w.WriteHeader(http.StatusNoContent)
+				case http.MethodPut:
+					w.Write([]byte(`[{"id": "3", "guild_id": "2", "name": "blep"}]`))
+				case http.MethodGet:
+					if tc.query != "" {
+						w.Write([]byte(`[{"id": "3", "guild_id": "2", "name": "blep"}]`))
+						return
+					}
+					fallthrough
+				default:
+					w.Write([]byte(`{"id": "3", "guild_id": "2", "name": "blep"}`))
+				}
+			}))
+			defer server.Close()
+
+			client := discord.NewClient("1234567890", discord.WithBaseURL(server.URL))
+			err := tc.call(context.Background(), client)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestApplicationCommandUnmarshal(t *testing.T) {
+	b := []byte(`{
+		"id": "1",
+		"type": 1,
+		"application_id": "2",
+		"name": "left-pad",
+		"name_localizations": {"fr": "remplissage"},
+		"description": "Left-pad a message",
+		"description_localizations": {"fr": "Remplir un message"},
+		"options": [{"name": "length", "description": "The length to pad to.", "type": 4, "required": true}],
+		"default_member_permissions": "8",
+		"dm_permission": false,
+		"nsfw": false,
+		"version": "3"
+	}`)
+
+	var command discord.ApplicationCommand
+	err := json.Unmarshal(b, &command)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if command.Version != "3" {
+		t.Errorf("expected version %s, got %s", "3", command.Version)
+	}
+
+	if len(command.Options) != 1 || command.Options[0].Name != "length" {
+		t.Errorf("expected a single option named %s, got %v", "length", command.Options)
+	}
+
+	if command.DefaultMemberPermissions == nil || *command.DefaultMemberPermissions != discord.PermissionAdministrator {
+		t.Errorf("expected default member permissions %s, got %v", discord.PermissionAdministrator, command.DefaultMemberPermissions)
+	}
+
+	if command.DMPermission == nil || *command.DMPermission {
+		t.Errorf("expected dm permission to be false, got %v", command.DMPermission)
+	}
+
+	if command.NSFW == nil || *command.NSFW {
+		t.Errorf("expected nsfw to be false, got %v", command.NSFW)
+	}
+
+	if command.NameLocalizations["fr"] != "remplissage" {
+		t.Errorf("expected name localization %s, got %s", "remplissage", command.NameLocalizations["fr"])
+	}
+
+	if command.DescriptionLocalizations["fr"] != "Remplir un message" {
+		t.Errorf("expected description localization %s, got %s", "Remplir un message", command.DescriptionLocalizations["fr"])
+	}
+}
+
+func TestSubcommandRouting(t *testing.T) {
+	tt := []struct {
+		name     string
+		options  []discord.ApplicationCommandInteractionDataOption
+		expected string
+	}{
+		{
+			name: "subcommand",
+			options: []discord.ApplicationCommandInteractionDataOption{
+				{Name: "left-pad", Type: discord.ApplicationCommandOptionTypeSubCommand},
+			},
+			expected: "text/left-pad",
+		},
+		{
+			name: "subcommand in group",
+			options: []discord.ApplicationCommandInteractionDataOption{
+				{
+					Name: "fun",
+					Type: discord.ApplicationCommandOptionTypeSubCommandGroup,
+					Options: []discord.ApplicationCommandInteractionDataOption{
+						{Name: "shuffle", Type: discord.ApplicationCommandOptionTypeSubCommand},
+					},
+				},
+			},
+			expected: "text/fun/shuffle",
+		},
+		{
+			name: "falls back to parent command",
+			options: []discord.ApplicationCommandInteractionDataOption{
+				{Name: "reverse", Type: discord.ApplicationCommandOptionTypeSubCommand},
+			},
+			expected: "text",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(&discord.Interaction{
+				Type: discord.InteractionTypeApplicationCommand,
+				Data: &discord.ApplicationCommandInteractionData{
+					Id:      "1234567890",
+					Name:    "text",
+					Options: tc.options,
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+			w := httptest.NewRecorder()
+
+			handler := discord.NewInteractionsHandler(nil)
+			handler.Validator = &passingValidator{}
+
+			respondWith := func(content string) discord.ApplicationCommandHandlerFunc {
+				return func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+					return discord.MessageResponse(content), nil
+				}
+			}
+
+			handler.RegisterApplicationCommandHandler("text", respondWith("text"))
+			text := handler.Group("text")
+			text.RegisterApplicationCommandHandler("left-pad", respondWith("text/left-pad"))
+			text.Group("fun").RegisterApplicationCommandHandler("shuffle", respondWith("text/fun/shuffle"))
+
+			handler.ServeHTTP(w, req)
+
+			var response discord.InteractionResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+
+			if *response.Data.Content != tc.expected {
+				t.Errorf("expected response content %s, got %s", tc.expected, *response.Data.Content)
+			}
+		})
+	}
+}
+
+type brokenComponent struct{}
+
+func (c brokenComponent) ComponentType() int {
+	return discord.ComponentTypeButton
+}
+
+func (c brokenComponent) MarshalJSON() ([]byte, error) {
+	return nil, fmt.Errorf("test error")
+}
+
+func TestInteractionsHandlerResponseEncodeFailure(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	deferredCalled := make(chan struct{}, 1)
+	handler.RegisterApplicationCommandHandler("broken", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		res := discord.DeferredMessageResponse(func(ctx *discord.InteractionContext) (*discord.InteractionResponseData, error) {
+			deferredCalled <- struct{}{}
+			return nil, nil
+		})
+		res.Data = &discord.InteractionResponseData{
+			Components: []discord.ActionRow{discord.NewActionRow(brokenComponent{})},
+		}
+		return res, nil
+	})
+
+	b, err := json.Marshal(&discord.Interaction{
+		Type: discord.InteractionTypeApplicationCommand,
+		Data: &discord.ApplicationCommandInteractionData{
+			Id:   "1234567890",
+			Name: "broken",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected response status code %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	if w.Header().Get("Content-Type") == "application/json; charset=utf-8" {
+		t.Error("expected error response not to be sent as JSON")
+	}
+
+	select {
+	case <-deferredCalled:
+		t.Error("expected deferred handler not to run when the response could not be sent")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInteractionsHandlerNilResponse(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+	handler.Use(discord.Recover())
+
+	handler.RegisterApplicationCommandHandler("empty", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		return nil, nil
+	})
+
+	w := serveCommand(t, handler, "empty")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected response status code %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestInteractionLogsOmitToken(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+	handler.RegisterApplicationCommandHandler("blep", noopHandler)
+
+	for _, interaction := range []*discord.Interaction{
+		{Id: "1", Type: discord.InteractionTypePing, Token: "SECRET-TOKEN"},
+		{Id: "2", Type: discord.InteractionTypeApplicationCommand, Token: "SECRET-TOKEN", Data: &discord.ApplicationCommandInteractionData{Name: "blep"}},
+		{Id: "3", Type: discord.InteractionTypeApplicationCommand, Token: "SECRET-TOKEN", Data: &discord.ApplicationCommandInteractionData{Name: "unknown"}},
+	} {
+		b, err := json.Marshal(interaction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b)))
+	}
+
+	if strings.Contains(logs.String(), "SECRET-TOKEN") {
+		t.Errorf("expected the interaction token not to be logged, got:\n%s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "2 (type 2, blep)") {
+		t.Errorf("expected interactions to be logged by ID, type and name, got:\n%s", logs.String())
+	}
+}
+
+func TestInteractionsHandlerOnServeMux(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	mux := http.NewServeMux()
+	mux.Handle("/interactions", handler)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	b, err := json.Marshal(&discord.Interaction{Type: discord.InteractionTypePing})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := http.Post(server.URL+"/interactions", "application/json", bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected response status code %d, got %d", http.StatusOK, res.StatusCode)
+	}
+
+	if res.Header.Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("expected response content type %s, got %s", "application/json; charset=utf-8", res.Header.Get("Content-Type"))
+	}
+
+	res, err = http.Get(server.URL + "/interactions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.Header.Get("Allow") != http.MethodPost {
+		t.Errorf("expected Allow header to be %s, got %s", http.MethodPost, res.Header.Get("Allow"))
+	}
+}
+
+func TestInteractionUnmarshal(t *testing.T) {
+	payload := `{
+		"id": "1",
+		"application_id": "2",
+		"type": 2,
+		"token": "token",
+		"version": 1,
+		"guild_id": "3",
+		"channel_id": "4",
+		"app_permissions": "442368",
+		"locale": "en-GB",
+		"guild_locale": "en-US",
+		"member": {
+			"user": {"id": "5", "username": "ghost", "discriminator": "0", "global_name": "Ghost"},
+			"roles": ["6"],
+			"joined_at": "2023-01-01T12:00:00.000000+00:00",
+			"deaf": false,
+			"mute": false,
+			"permissions": "2147483647"
+		},
+		"data": {
+			"id": "7",
+			"name": "blep",
+			"type": 1,
+			"options": [{"name": "role", "type": 8, "value": "6"}],
+			"resolved": {
+				"roles": {"6": {"id": "6", "name": "Mods", "color": 0, "hoist": false, "position": 1, "permissions": "0", "managed": false, "mentionable": true}}
+			}
+		}
+	}`
+
+	var interaction discord.Interaction
+	if err := json.Unmarshal([]byte(payload), &interaction); err != nil {
+		t.Fatal(err)
+	}
+
+	user := interaction.InvokingUser()
+	if user == nil || user.DisplayName() != "Ghost" {
+		t.Errorf("expected invoking user %s, got %+v", "Ghost", user)
+	}
+
+	if interaction.Member.JoinedAt.Year() != 2023 {
+		t.Errorf("expected member to have joined in %d, got %d", 2023, interaction.Member.JoinedAt.Year())
+	}
+
+	if interaction.Locale != "en-GB" || interaction.GuildLocale != "en-US" {
+		t.Errorf("expected locales %s and %s, got %s and %s", "en-GB", "en-US", interaction.Locale, interaction.GuildLocale)
+	}
+
+	role, ok := interaction.ApplicationCommandData().Resolved.Roles["6"]
+	if !ok || role.Name != "Mods" {
+		t.Errorf("expected resolved role %s, got %+v", "Mods", role)
+	}
+}
+
+func TestContextMenuCommands(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	handler.RegisterMessageCommandHandler("Inspect", func(ctx *discord.InteractionContext, message *discord.Message) (*discord.InteractionResponse, error) {
+		return discord.MessageResponse("message " + message.Content), nil
+	})
+	handler.RegisterUserCommandHandler("Inspect", func(ctx *discord.InteractionContext, user *discord.User, member *discord.Member) (*discord.InteractionResponse, error) {
+		if member == nil || *member.Nick != "spooky" {
+			t.Errorf("expected member with nick %s, got %+v", "spooky", member)
+		}
+		return discord.MessageResponse("user " + user.Username), nil
+	})
+
+	tt := []struct {
+		name     string
+		data     *discord.ApplicationCommandInteractionData
+		expected string
+	}{
+		{
+			name: "message command",
+			data: &discord.ApplicationCommandInteractionData{
+				Name:     "Inspect",
+				Type:     discord.ApplicationCommandTypeMessage,
+				TargetId: "1",
+				Resolved: &discord.ResolvedData{
+					Messages: map[string]*discord.Message{"1": {Id: "1", Content: "hello"}},
+				},
+			},
+			expected: "message hello",
+		},
+		{
+			name: "user command",
+			data: &discord.ApplicationCommandInteractionData{
+				Name:     "Inspect",
+				Type:     discord.ApplicationCommandTypeUser,
+				TargetId: "2",
+				Resolved: &discord.ResolvedData{
+					Users:   map[string]*discord.User{"2": {Id: "2", Username: "ghost"}},
+					Members: map[string]*discord.Member{"2": {Nick: discord.String("spooky")}},
+				},
+			},
+			expected: "user ghost",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(&discord.Interaction{
+				Type: discord.InteractionTypeApplicationCommand,
+				Data: tc.data,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			var response discord.InteractionResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+
+			if *response.Data.Content != tc.expected {
+				t.Errorf("expected response content %s, got %s", tc.expected, *response.Data.Content)
+			}
+		})
+	}
+}
+
+func TestWithDefaultFlags(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	handler.RegisterApplicationCommandHandler("quiet", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		return discord.MessageResponse("shh"), nil
+	}, discord.WithDefaultFlags(discord.MessageFlagEphemeral))
+	handler.RegisterApplicationCommandHandler("loud", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		res := discord.MessageResponse("hey")
+		res.Data.Flags = discord.MessageFlagSuppressEmbeds
+		return res, nil
+	}, discord.WithDefaultFlags(discord.MessageFlagEphemeral))
+
+	tt := []struct {
+		name     string
+		expected discord.MessageFlags
+	}{
+		{
+			name:     "quiet",
+			expected: discord.MessageFlagEphemeral,
+		},
+		{
+			name:     "loud",
+			expected: discord.MessageFlagSuppressEmbeds,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(&discord.Interaction{
+				Type: discord.InteractionTypeApplicationCommand,
+				Data: &discord.ApplicationCommandInteractionData{Name: tc.name},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			var response discord.InteractionResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Data.Flags != tc.expected {
+				t.Errorf("expected flags %d, got %d", tc.expected, response.Data.Flags)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits Discord places on embeds, in characters.
+const (
+	MaxEmbedTitleLength       = 256
+	MaxEmbedDescriptionLength = 4096
+	MaxEmbedFields            = 25
+	MaxEmbedFieldNameLength   = 256
+	MaxEmbedFieldValueLength  = 1024
+	MaxEmbedFooterTextLength  = 2048
+	MaxEmbedAuthorNameLength  = 256
+	// MaxEmbedTotalLength applies to the sum of all text in an embed.
+	MaxEmbedTotalLength = 6000
+)
+
+type EmbedFooter struct {
+	Text    string `json:"text"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+type EmbedImage struct {
+	URL    string `json:"url"`
+	Height int    `json:"height,omitempty"`
+	Width  int    `json:"width,omitempty"`
+}
+
+type EmbedAuthor struct {
+	Name    string `json:"name"`
+	URL     string `json:"url,omitempty"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type Embed struct {
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	URL         string        `json:"url,omitempty"`
+	Timestamp   *time.Time    `json:"timestamp,omitempty"`
+	Color       int           `json:"color,omitempty"`
+	Footer      *EmbedFooter  `json:"footer,omitempty"`
+	Image       *EmbedImage   `json:"image,omitempty"`
+	Thumbnail   *EmbedImage   `json:"thumbnail,omitempty"`
+	Author      *EmbedAuthor  `json:"author,omitempty"`
+	Fields      []*EmbedField `json:"fields,omitempty"`
+}
+
+// EmbedLimitError is returned when an embed exceeds one of Discord's limits.
+type EmbedLimitError struct {
+	Field  string
+	Length int
+	Limit  int
+}
+
+func (e *EmbedLimitError) Error() string {
+	return fmt.Sprintf("embed %s is too long: %d exceeds the limit of %d", e.Field, e.Length, e.Limit)
+}
+
+// Length returns the number of characters in the embed that count towards MaxEmbedTotalLength.
+func (e *Embed) Length() int {
+	length := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+	if e.Footer != nil {
+		length += utf8.RuneCountInString(e.Footer.Text)
+	}
+	if e.Author != nil {
+		length += utf8.RuneCountInString(e.Author.Name)
+	}
+	for _, field := range e.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	return length
+}
+
+// Validate returns an *EmbedLimitError if the embed would be rejected by Discord for being too long.
+func (e *Embed) Validate() error {
+	check := func(field string, value string, limit int) error {
+		if length := utf8.RuneCountInString(value); length > limit {
+			return &EmbedLimitError{Field: field, Length: length, Limit: limit}
+		}
+		return nil
+	}
+
+	err := check("title", e.Title, MaxEmbedTitleLength)
+	if err != nil {
+		return err
+	}
+
+	err = check("description", e.Description, MaxEmbedDescriptionLength)
+	if err != nil {
+		return err
+	}
+
+	if e.Footer != nil {
+		err = check("footer text", e.Footer.Text, MaxEmbedFooterTextLength)
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.Author != nil {
+		err = check("author name", e.Author.Name, MaxEmbedAuthorNameLength)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(e.Fields) > MaxEmbedFields {
+		return &EmbedLimitError{Field: "fields", Length: len(e.Fields), Limit: MaxEmbedFields}
+	}
+
+	for i, field := range e.Fields {
+		err = check(fmt.Sprintf("field %d name", i), field.Name, MaxEmbedFieldNameLength)
+		if err != nil {
+			return err
+		}
+
+		err = check(fmt.Sprintf("field %d value", i), field.Value, MaxEmbedFieldValueLength)
+		if err != nil {
+			return err
+		}
+	}
+
+	if length := e.Length(); length > MaxEmbedTotalLength {
+		return &EmbedLimitError{Field: "total", Length: length, Limit: MaxEmbedTotalLength}
+	}
+
+	return nil
+}
+
+// EmbedBuilder builds an Embed, checking it against Discord's limits.
+type EmbedBuilder struct {
+	embed Embed
+}
+
+// NewEmbed creates an EmbedBuilder for an empty embed.
+func NewEmbed() *EmbedBuilder {
+	return &EmbedBuilder{}
+}
+
+func (b *EmbedBuilder) SetTitle(title string) *EmbedBuilder {
+	b.embed.Title = title
+	return b
+}
+
+func (b *EmbedBuilder) SetDescription(description string) *EmbedBuilder {
+	b.embed.Description = description
+	return b
+}
+
+func (b *EmbedBuilder) SetURL(url string) *EmbedBuilder {
+	b.embed.URL = url
+	return b
+}
+
+// SetColor sets the color of the embed's side bar, as an RGB value such as 0x5865F2.
+func (b *EmbedBuilder) SetColor(color int) *EmbedBuilder {
+	b.embed.Color = color
+	return b
+}
+
+func (b *EmbedBuilder) SetTimestamp(timestamp time.Time) *EmbedBuilder {
+	b.embed.Timestamp = &timestamp
+	return b
+}
+
+func (b *EmbedBuilder) SetFooter(text string, iconURL string) *EmbedBuilder {
+	b.embed.Footer = &EmbedFooter{Text: text, IconURL: iconURL}
+	return b
+}
+
+func (b *EmbedBuilder) SetImage(url string) *EmbedBuilder {
+	b.embed.Image = &EmbedImage{URL: url}
+	return b
+}
+
+func (b *EmbedBuilder) SetThumbnail(url string) *EmbedBuilder {
+	b.embed.Thumbnail = &EmbedImage{URL: url}
+	return b
+}
+
+func (b *EmbedBuilder) SetAuthor(name string, url string, iconURL string) *EmbedBuilder {
+	b.embed.Author = &EmbedAuthor{Name: name, URL: url, IconURL: iconURL}
+	return b
+}
+
+func (b *EmbedBuilder) AddField(name string, value string, inline bool) *EmbedBuilder {
+	b.embed.Fields = append(b.embed.Fields, &EmbedField{Name: name, Value: value, Inline: inline})
+	return b
+}
+
+// Build returns the embed, or an *EmbedLimitError if it exceeds any of Discord's limits.
+func (b *EmbedBuilder) Build() (*Embed, error) {
+	err := b.embed.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	embed := b.embed
+	embed.Fields = append([]*EmbedField(nil), b.embed.Fields...)
+	return &embed, nil
+}
+
+// EmbedResponse is a convenience function for creating a response that sends the given embeds.
+func EmbedResponse(embeds ...*Embed) *InteractionResponse {
+	return &InteractionResponse{
+		Type: InteractionResponseTypeChannelMessageWithSource,
+		Data: &InteractionResponseData{
+			Embeds: embeds,
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package discord_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func TestEmbedBuilder(t *testing.T) {
+	tooManyFields := discord.NewEmbed()
+	for i := 0; i < discord.MaxEmbedFields+1; i++ {
+		tooManyFields.AddField(fmt.Sprintf("field %d", i), "value", true)
+	}
+
+	tooLongOverall := discord.NewEmbed().SetDescription(strings.Repeat("a", discord.MaxEmbedDescriptionLength))
+	for i := 0; i < 2; i++ {
+		tooLongOverall.AddField("field", strings.Repeat("b", discord.MaxEmbedFieldValueLength), false)
+	}
+
+	tt := []struct {
+		name    string
+		builder *discord.EmbedBuilder
+		field   string
+	}{
+		{
+			name:    "valid embed",
+			builder: discord.NewEmbed().SetTitle("Title").SetDescription("Description").AddField("Name", "Value", false),
+		},
+		{
+			name:    "title counts characters rather than bytes",
+			builder: discord.NewEmbed().SetTitle(strings.Repeat("█", discord.MaxEmbedTitleLength)),
+		},
+		{
+			name:    "title too long",
+			builder: discord.NewEmbed().SetTitle(strings.Repeat("a", discord.MaxEmbedTitleLength+1)),
+			field:   "title",
+		},
+		{
+			name:    "description too long",
+			builder: discord.NewEmbed().SetDescription(strings.Repeat("a", discord.MaxEmbedDescriptionLength+1)),
+			field:   "description",
+		},
+		{
+			name:    "too many fields",
+			builder: tooManyFields,
+			field:   "fields",
+		},
+		{
+			name:    "too long overall",
+			builder: tooLongOverall,
+			field:   "total",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			embed, err := tc.builder.Build()
+			if tc.field == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if embed == nil {
+					t.Fatal("expected an embed")
+				}
+				return
+			}
+
+			var limitErr *discord.EmbedLimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("expected an EmbedLimitError, got %v", err)
+			}
+
+			if limitErr.Field != tc.field {
+				t.Errorf("expected limit error for %s, got %s", tc.field, limitErr.Field)
+			}
+		})
+	}
+}
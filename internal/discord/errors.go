@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// APIError is returned when the Discord API responds with an error status code.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+	// Code is Discord's JSON error code, such as 50035 for an invalid form body.
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Errors is the nested tree of per-field errors, keyed by field name or array index.
+	// Use FieldErrors to read it as a flat list.
+	Errors json.RawMessage `json:"errors,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "discord: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ": %s", e.Message)
+	}
+	if e.Code != 0 {
+		fmt.Fprintf(&sb, " (%d)", e.Code)
+	}
+	for _, fieldErr := range e.FieldErrors() {
+		fmt.Fprintf(&sb, "; %s", fieldErr)
+	}
+	return sb.String()
+}
+
+// FieldError is a single error from the errors tree of an APIError.
+type FieldError struct {
+	// Path is the location of the field in the request body, such as "options.0.name".
+	Path    string `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// FieldErrors flattens the errors tree into a list of errors, sorted by path.
+func (e *APIError) FieldErrors() []FieldError {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	var fieldErrs []FieldError
+	flattenFieldErrors(e.Errors, "", &fieldErrs)
+	sort.SliceStable(fieldErrs, func(i, j int) bool {
+		return fieldErrs[i].Path < fieldErrs[j].Path
+	})
+	return fieldErrs
+}
+
+// flattenFieldErrors walks a node of the errors tree, collecting the errors listed under each "_errors" key.
+func flattenFieldErrors(data json.RawMessage, path string, fieldErrs *[]FieldError) {
+	var node map[string]json.RawMessage
+	if json.Unmarshal(data, &node) != nil {
+		return
+	}
+
+	for key, child := range node {
+		if key == "_errors" {
+			var errs []FieldError
+			if json.Unmarshal(child, &errs) != nil {
+				continue
+			}
+			for _, fieldErr := range errs {
+				fieldErr.Path = path
+				*fieldErrs = append(*fieldErrs, fieldErr)
+			}
+			continue
+		}
+
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		flattenFieldErrors(child, childPath, fieldErrs)
+	}
+}
@@ -0,0 +1,158 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// File is a file to upload alongside a message.
+type File struct {
+	Name        string
+	ContentType string
+	Description string
+	Reader      io.Reader
+}
+
+// NewTextFile creates a plain text file with the given name and contents.
+func NewTextFile(name string, contents string) *File {
+	return &File{
+		Name:        name,
+		ContentType: "text/plain; charset=utf-8",
+		Reader:      strings.NewReader(contents),
+	}
+}
+
+// PartialAttachment describes an attachment when sending a message.
+// New files are referred to by their index in Files, and existing attachments by their ID.
+type PartialAttachment struct {
+	Id          string `json:"id"`
+	Filename    string `json:"filename,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// attachFiles adds an attachment for each file that isn't already described by one.
+func (d *InteractionResponseData) attachFiles() {
+	for i, file := range d.Files {
+		id := strconv.Itoa(i)
+
+		found := false
+		for _, attachment := range d.Attachments {
+			if attachment.Id == id {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		d.Attachments = append(d.Attachments, &PartialAttachment{
+			Id:          id,
+			Filename:    file.Name,
+			Description: file.Description,
+		})
+	}
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// encodeMultipart encodes payload as the payload_json field of a multipart form,
+// followed by each file as files[n]. It returns the encoded form and its content type.
+func encodeMultipart(payload interface{}, files []*File) (*bytes.Buffer, string, error) {
+	buf := &bytes.Buffer{}
+	mw := multipart.NewWriter(buf)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+
+	enc := json.NewEncoder(part)
+	enc.SetEscapeHTML(false)
+	err = enc.Encode(payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i, file := range files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files[%d]"; filename="%s"`, i, quoteEscaper.Replace(file.Name)))
+		header.Set("Content-Type", contentType)
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+
+		_, err = io.Copy(part, file.Reader)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read file %s: %w", file.Name, err)
+		}
+	}
+
+	err = mw.Close()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return buf, mw.FormDataContentType(), nil
+}
+
+// encodeMessageBody encodes data as JSON, or as a multipart form if it has any files.
+// It returns the encoded body and its content type.
+func encodeMessageBody(data *InteractionResponseData) (*bytes.Buffer, string, error) {
+	buf := &bytes.Buffer{}
+	if data == nil {
+		return buf, "application/json", nil
+	}
+
+	if len(data.Files) > 0 {
+		data.attachFiles()
+		return encodeMultipart(data, data.Files)
+	}
+
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return buf, "application/json", nil
+}
+
+// writeMultipart writes v and files as a multipart response.
+// Like writeJSON, the response is fully encoded before any headers are written.
+func writeMultipart(w http.ResponseWriter, v interface{}, files []*File) bool {
+	buf, contentType, err := encodeMultipart(v, files)
+	if err != nil {
+		log.Printf("failed to encode response: %s\n", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return false
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		log.Printf("failed to write response body: %s\n", err)
+	}
+
+	return true
+}
@@ -0,0 +1,130 @@
+package discord_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+// readMultipart decodes the payload_json part of a multipart message body into v,
+// and returns the files in the body keyed by field name as "filename:contents".
+func readMultipart(t *testing.T, contentType string, body io.Reader, v interface{}) map[string]string {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType != "multipart/form-data" {
+		t.Fatalf("expected content type %s, got %s", "multipart/form-data", mediaType)
+	}
+
+	files := make(map[string]string)
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if part.FormName() == "payload_json" {
+			err = json.NewDecoder(part).Decode(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		b, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[part.FormName()] = part.FileName() + ":" + string(b)
+	}
+
+	return files
+}
+
+func TestMultipartResponse(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+	handler.RegisterApplicationCommandHandler("test", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		return &discord.InteractionResponse{
+			Type: discord.InteractionResponseTypeChannelMessageWithSource,
+			Data: &discord.InteractionResponseData{
+				Content: discord.String("here is a file"),
+				Files:   []*discord.File{discord.NewTextFile("test.txt", "hello")},
+			},
+		}, nil
+	})
+
+	w := serveCommand(t, handler, "test")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response discord.InteractionResponse
+	files := readMultipart(t, w.Header().Get("Content-Type"), w.Body, &response)
+	if response.Type != discord.InteractionResponseTypeChannelMessageWithSource {
+		t.Errorf("expected response type %d, got %d", discord.InteractionResponseTypeChannelMessageWithSource, response.Type)
+	}
+
+	if response.Data == nil {
+		t.Fatal("expected response data in payload_json")
+	}
+
+	if response.Data.Content == nil || *response.Data.Content != "here is a file" {
+		t.Errorf("expected content %q in payload_json", "here is a file")
+	}
+
+	if len(response.Data.Attachments) != 1 {
+		t.Fatalf("expected %d attachment, got %d", 1, len(response.Data.Attachments))
+	}
+	if response.Data.Attachments[0].Id != "0" || response.Data.Attachments[0].Filename != "test.txt" {
+		t.Errorf("expected attachment %s with filename %s, got %s with filename %s", "0", "test.txt", response.Data.Attachments[0].Id, response.Data.Attachments[0].Filename)
+	}
+
+	if files["files[0]"] != "test.txt:hello" {
+		t.Errorf("expected files[0] to be %q, got %q", "test.txt:hello", files["files[0]"])
+	}
+}
+
+func TestCreateFollowupMessageWithFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data discord.InteractionResponseData
+		files := readMultipart(t, r.Header.Get("Content-Type"), r.Body, &data)
+
+		if len(data.Attachments) != 1 {
+			t.Errorf("expected %d attachment, got %d", 1, len(data.Attachments))
+		}
+
+		if files["files[0]"] != "result.txt:contents" {
+			t.Errorf("expected files[0] to be %q, got %q", "result.txt:contents", files["files[0]"])
+		}
+
+		w.Write([]byte(`{"id": "1", "channel_id": "2"}`))
+	}))
+	defer server.Close()
+
+	client := discord.NewClient("")
+	serverURL, _ := url.Parse(server.URL)
+	client.BaseURL = serverURL
+
+	_, err := client.InteractionWebhooks.CreateFollowupMessage(context.Background(), "1234567890", "token", &discord.InteractionResponseData{
+		Files: []*discord.File{discord.NewTextFile("result.txt", "contents")},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package discord
+
+// MessageFlags is a bitset of flags that change how a message is displayed.
+type MessageFlags int
+
+const (
+	// MessageFlagSuppressEmbeds stops embeds being generated for links in the message.
+	MessageFlagSuppressEmbeds MessageFlags = 1 << 2
+	// MessageFlagEphemeral makes the message visible only to the user who invoked the interaction.
+	MessageFlagEphemeral MessageFlags = 1 << 6
+	// MessageFlagSuppressNotifications sends the message without push or desktop notifications.
+	MessageFlagSuppressNotifications MessageFlags = 1 << 12
+)
+
+// Has returns true if all of the given flags are set.
+func (f MessageFlags) Has(flags MessageFlags) bool {
+	return f&flags == flags
+}
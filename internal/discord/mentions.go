@@ -0,0 +1,49 @@
+package discord
+
+import "encoding/json"
+
+// AllowedMentionType is a type of mention that Discord should parse from message content.
+type AllowedMentionType string
+
+const (
+	AllowedMentionTypeRoles    AllowedMentionType = "roles"
+	AllowedMentionTypeUsers    AllowedMentionType = "users"
+	AllowedMentionTypeEveryone AllowedMentionType = "everyone"
+)
+
+// AllowedMentions controls which mentions in a message actually notify anyone.
+// Mentions not allowed here are still rendered, but nobody is pinged.
+type AllowedMentions struct {
+	// Parse lists the types of mention to parse from the content.
+	// A nil or empty Parse allows no mentions other than those listed in Roles and Users.
+	Parse []AllowedMentionType `json:"parse"`
+	// Roles lists the IDs of roles that may be mentioned. It can't be used alongside parsing roles.
+	Roles []string `json:"roles,omitempty"`
+	// Users lists the IDs of users that may be mentioned. It can't be used alongside parsing users.
+	Users []string `json:"users,omitempty"`
+	// RepliedUser controls whether the author of a replied-to message is mentioned.
+	RepliedUser bool `json:"replied_user,omitempty"`
+}
+
+func (m AllowedMentions) MarshalJSON() ([]byte, error) {
+	type alias AllowedMentions
+	// Discord only disables parsing when it receives an empty list, so never send null.
+	if m.Parse == nil {
+		m.Parse = []AllowedMentionType{}
+	}
+	return json.Marshal(alias(m))
+}
+
+// NoMentions returns allowed mentions that stop a message from pinging anyone.
+func NoMentions() *AllowedMentions {
+	return &AllowedMentions{
+		Parse: []AllowedMentionType{},
+	}
+}
+
+// AllMentions returns allowed mentions that let every mention in a message ping, including @everyone.
+func AllMentions() *AllowedMentions {
+	return &AllowedMentions{
+		Parse: []AllowedMentionType{AllowedMentionTypeRoles, AllowedMentionTypeUsers, AllowedMentionTypeEveryone},
+	}
+}
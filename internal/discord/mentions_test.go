@@ -0,0 +1,143 @@
+package discord_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+type rawAllowedMentions struct {
+	Parse []string `json:"parse"`
+	Roles []string `json:"roles"`
+	Users []string `json:"users"`
+}
+
+func assertNoMentions(t *testing.T, allowed *rawAllowedMentions) {
+	t.Helper()
+
+	if allowed == nil {
+		t.Fatal("expected allowed_mentions to be set")
+	}
+
+	if allowed.Parse == nil || len(allowed.Parse) != 0 {
+		t.Errorf("expected parse to be an empty list, got %v", allowed.Parse)
+	}
+
+	if len(allowed.Roles) != 0 || len(allowed.Users) != 0 {
+		t.Errorf("expected no roles or users to be allowed, got %v and %v", allowed.Roles, allowed.Users)
+	}
+}
+
+func TestEchoedContentCannotMention(t *testing.T) {
+	const input = "@everyone @here <@&1234> <@5678> hello"
+
+	edited := make(chan *rawAllowedMentions, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data struct {
+			AllowedMentions *rawAllowedMentions `json:"allowed_mentions"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			t.Error(err)
+		}
+
+		w.Write([]byte(`{"id": "1"}`))
+		edited <- data.AllowedMentions
+	}))
+	defer server.Close()
+
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+	serverURL, _ := url.Parse(server.URL)
+	handler.Client.BaseURL = serverURL
+
+	handler.RegisterApplicationCommandHandler("echo", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		message, _ := ctx.StringOption("message")
+		return discord.MessageResponse(message), nil
+	})
+	handler.RegisterApplicationCommandHandler("deferred-echo", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		message, _ := ctx.StringOption("message")
+		return discord.DeferredMessageResponse(func(ctx *discord.InteractionContext) (*discord.InteractionResponseData, error) {
+			return &discord.InteractionResponseData{Content: discord.String(message)}, nil
+		}), nil
+	})
+
+	serve := func(name string) *httptest.ResponseRecorder {
+		b, err := json.Marshal(&discord.Interaction{
+			Type:  discord.InteractionTypeApplicationCommand,
+			Token: "token",
+			Data: &discord.ApplicationCommandInteractionData{
+				Name: name,
+				Options: []discord.ApplicationCommandInteractionDataOption{
+					{Name: "message", Type: discord.ApplicationCommandOptionTypeString, Value: input},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("initial response", func(t *testing.T) {
+		var response struct {
+			Data struct {
+				Content         string              `json:"content"`
+				AllowedMentions *rawAllowedMentions `json:"allowed_mentions"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(serve("echo").Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+
+		if response.Data.Content != input {
+			t.Errorf("expected content %s, got %s", input, response.Data.Content)
+		}
+
+		assertNoMentions(t, response.Data.AllowedMentions)
+	})
+
+	t.Run("deferred edit", func(t *testing.T) {
+		serve("deferred-echo")
+
+		select {
+		case allowed := <-edited:
+			assertNoMentions(t, allowed)
+		case <-time.After(time.Second):
+			t.Fatal("expected original response to be edited")
+		}
+	})
+}
+
+func TestHandlerCanOptInToMentions(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	handler.RegisterApplicationCommandHandler("ping", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		res := discord.MessageResponse("<@5678>")
+		res.Data.AllowedMentions = &discord.AllowedMentions{Users: []string{"5678"}}
+		return res, nil
+	})
+
+	var response struct {
+		Data struct {
+			AllowedMentions *rawAllowedMentions `json:"allowed_mentions"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(serveCommand(t, handler, "ping").Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Data.AllowedMentions.Users) != 1 || response.Data.AllowedMentions.Users[0] != "5678" {
+		t.Errorf("expected user %s to be allowed, got %v", "5678", response.Data.AllowedMentions.Users)
+	}
+}
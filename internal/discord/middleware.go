@@ -0,0 +1,205 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// Middleware wraps a handler to add behaviour that runs before or after it.
+// Middleware applies to application command, message component and modal handlers.
+type Middleware func(next ApplicationCommandHandlerFunc) ApplicationCommandHandlerFunc
+
+// Use adds middleware to the handler.
+// Middleware runs in the order it was added, so the first middleware added is the outermost.
+func (h *InteractionsHandler) Use(mw ...Middleware) {
+	h.middleware = append(h.middleware, mw...)
+}
+
+// wrap applies the handler's middleware to the given handler.
+func (h *InteractionsHandler) wrap(handler ApplicationCommandHandlerFunc) ApplicationCommandHandlerFunc {
+	for i := len(h.middleware) - 1; i >= 0; i-- {
+		handler = h.middleware[i](handler)
+	}
+	return handler
+}
+
+// interactionName describes an interaction for logging, e.g. "text/left-pad" or "shuffle:reshuffle".
+func interactionName(interaction *Interaction) string {
+	switch data := interaction.Data.(type) {
+	case *ApplicationCommandInteractionData:
+		return data.CommandPath()
+	case *MessageComponentInteractionData:
+		return data.CustomId
+	case *ModalSubmitInteractionData:
+		return data.CustomId
+	}
+	return "unknown"
+}
+
+// Recover recovers from panics in handlers, logging the panic and
+// answering with an error message that only the invoking user can see.
+func Recover() Middleware {
+	return func(next ApplicationCommandHandlerFunc) ApplicationCommandHandlerFunc {
+		return func(ctx *InteractionContext) (res *InteractionResponse, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("panic while handling %s: %v\n%s", interactionName(ctx.Interaction), r, debug.Stack())
+					res, err = EphemeralResponse(defaultErrorMessage), nil
+				}
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+// LogTiming logs how long each handler takes to respond.
+func LogTiming() Middleware {
+	return func(next ApplicationCommandHandlerFunc) ApplicationCommandHandlerFunc {
+		return func(ctx *InteractionContext) (*InteractionResponse, error) {
+			start := time.Now()
+			res, err := next(ctx)
+			log.Printf("handled %s in %s\n", interactionName(ctx.Interaction), time.Since(start))
+			return res, err
+		}
+	}
+}
+
+const defaultErrorMessage = "Something went wrong while handling this command."
+
+// UserError is an error with a message that is safe to show to users.
+type UserError struct {
+	Message string
+	Err     error
+}
+
+func (e *UserError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *UserError) Unwrap() error {
+	return e.Err
+}
+
+// NewUserError creates an error that MapErrors shows to the user as-is.
+func NewUserError(message string) error {
+	return &UserError{Message: message}
+}
+
+// DefaultErrorMessage shows the message of any UserError, and a generic message for all other errors.
+func DefaultErrorMessage(err error) string {
+	var userErr *UserError
+	if errors.As(err, &userErr) {
+		return userErr.Message
+	}
+	return defaultErrorMessage
+}
+
+// MapErrors turns errors returned by handlers into an error message that only the invoking user can see,
+// instead of failing the request. If fn is nil, DefaultErrorMessage is used.
+func MapErrors(fn func(err error) string) Middleware {
+	if fn == nil {
+		fn = DefaultErrorMessage
+	}
+
+	return func(next ApplicationCommandHandlerFunc) ApplicationCommandHandlerFunc {
+		return func(ctx *InteractionContext) (*InteractionResponse, error) {
+			// errors from handlers that finish after deferring are reported by the deferred response.
+			ctx.errorMessage = fn
+
+			res, err := next(ctx)
+			if err != nil {
+				log.Printf("failed to handle %s: %s\n", interactionName(ctx.Interaction), err)
+				return EphemeralResponse(fn(err)), nil
+			}
+			return res, nil
+		}
+	}
+}
+
+type handlerResult struct {
+	res *InteractionResponse
+	err error
+}
+
+// AutoDefer sends a deferred response if a handler has not finished within margin of the end of
+// Discord's response window. The handler keeps running in the background, and its result replaces
+// the deferred response once it is ready. The deferred response uses the handler's default flags,
+// so that commands registered with WithDefaultFlags(MessageFlagEphemeral) stay ephemeral.
+// Handlers that may respond with a modal should not use AutoDefer, as modals can only be sent as the initial response.
+func AutoDefer(margin time.Duration) Middleware {
+	return func(next ApplicationCommandHandlerFunc) ApplicationCommandHandlerFunc {
+		return func(ctx *InteractionContext) (*InteractionResponse, error) {
+			deadline, ok := ctx.Context().Deadline()
+			if !ok {
+				return next(ctx)
+			}
+
+			// the handler may outlive the request, so it can't use the request's context.
+			bg := ctx.detach()
+			done := make(chan handlerResult, 1)
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("panic while handling %s: %v\n%s", interactionName(ctx.Interaction), r, debug.Stack())
+						done <- handlerResult{err: fmt.Errorf("panic: %v", r)}
+					}
+				}()
+
+				res, err := next(bg)
+				done <- handlerResult{res: res, err: err}
+			}()
+
+			timer := time.NewTimer(time.Until(deadline) - margin)
+			defer timer.Stop()
+
+			select {
+			case result := <-done:
+				bg.cancel()
+				return result.res, result.err
+			case <-timer.C:
+			}
+
+			log.Printf("deferring response to %s\n", interactionName(ctx.Interaction))
+
+			deferredResponse := DeferredMessageResponse
+			if ctx.Interaction.Type == InteractionTypeMessageComponent {
+				deferredResponse = DeferredUpdateMessageResponse
+			}
+
+			res := deferredResponse(func(_ *InteractionContext) (*InteractionResponseData, error) {
+				defer bg.cancel()
+
+				result := <-done
+				if result.err != nil {
+					return nil, result.err
+				}
+
+				if result.res == nil {
+					return nil, errors.New("handler returned no response")
+				}
+
+				if result.res.deferred != nil {
+					return result.res.deferred(bg)
+				}
+
+				if result.res.Data == nil || result.res.Type == InteractionResponseTypeModal {
+					return nil, fmt.Errorf("response of type %d can't be sent after deferring", result.res.Type)
+				}
+
+				return result.res.Data, nil
+			})
+			// the handler's options are applied inside the middleware, so they won't be applied to this response.
+			if res.Type == InteractionResponseTypeDeferredChannelMessageWithSource && ctx.defaultFlags != 0 {
+				res.Data = &InteractionResponseData{Flags: ctx.defaultFlags}
+			}
+			return res, nil
+		}
+	}
+}
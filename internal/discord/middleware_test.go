@@ -0,0 +1,367 @@
+package discord_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+// serveCommand serves an invocation of the named command with the given options,
+// which can also be used to invoke a subcommand.
+func serveCommand(t *testing.T, handler *discord.InteractionsHandler, name string, options ...discord.ApplicationCommandInteractionDataOption) *httptest.ResponseRecorder {
+	t.Helper()
+
+	b, err := json.Marshal(&discord.Interaction{
+		Type: discord.InteractionTypeApplicationCommand,
+		Data: &discord.ApplicationCommandInteractionData{
+			Id:      "1234567890",
+			Name:    name,
+			Options: options,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	var calls []string
+	record := func(name string) discord.Middleware {
+		return func(next discord.ApplicationCommandHandlerFunc) discord.ApplicationCommandHandlerFunc {
+			return func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+				calls = append(calls, name)
+				return next(ctx)
+			}
+		}
+	}
+
+	handler.Use(record("first"), record("second"))
+	handler.RegisterApplicationCommandHandler("blep", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		calls = append(calls, "handler")
+		return discord.MessageResponse("blep"), nil
+	})
+
+	serveCommand(t, handler, "blep")
+
+	if strings.Join(calls, ",") != "first,second,handler" {
+		t.Errorf("expected calls %s, got %s", "first,second,handler", strings.Join(calls, ","))
+	}
+}
+
+func TestRecover(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+	handler.Use(discord.Recover())
+
+	handler.RegisterApplicationCommandHandler("blep", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		panic("oh no")
+	})
+
+	w := serveCommand(t, handler, "blep")
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected response status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response discord.InteractionResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Data == nil || !response.Data.Flags.Has(discord.MessageFlagEphemeral) {
+		t.Errorf("expected an ephemeral response, got %+v", response.Data)
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	tt := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "user error",
+			err:      fmt.Errorf("wrapped: %w", discord.NewUserError("Nope")),
+			expected: "Nope",
+		},
+		{
+			name:     "internal error",
+			err:      fmt.Errorf("database is on fire"),
+			expected: "Something went wrong while handling this command.",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := discord.NewInteractionsHandler(nil)
+			handler.Validator = &passingValidator{}
+			handler.Use(discord.MapErrors(nil))
+
+			handler.RegisterApplicationCommandHandler("blep", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+				return nil, tc.err
+			})
+
+			w := serveCommand(t, handler, "blep")
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected response status code %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var response discord.InteractionResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+
+			if *response.Data.Content != tc.expected {
+				t.Errorf("expected response content %s, got %s", tc.expected, *response.Data.Content)
+			}
+		})
+	}
+}
+
+func TestInteractionContextDeadline(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+
+	handler.RegisterApplicationCommandHandler("blep", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		deadline, ok := ctx.Context().Deadline()
+		if !ok {
+			t.Fatal("expected context to have a deadline")
+		}
+
+		if remaining := time.Until(deadline); remaining > discord.ResponseWindow {
+			t.Errorf("expected deadline within %s, got %s", discord.ResponseWindow, remaining)
+		}
+
+		return discord.MessageResponse("blep"), nil
+	})
+
+	serveCommand(t, handler, "blep")
+}
+
+func TestAutoDefer(t *testing.T) {
+	edited := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data discord.InteractionResponseData
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			t.Error(err)
+		}
+
+		w.Write([]byte(`{"id": "1"}`))
+		edited <- *data.Content
+	}))
+	defer server.Close()
+
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+	serverURL, _ := url.Parse(server.URL)
+	handler.Client.BaseURL = serverURL
+
+	// defer almost immediately, rather than waiting for most of the response window.
+	handler.Use(discord.AutoDefer(discord.ResponseWindow - 10*time.Millisecond))
+
+	handler.RegisterApplicationCommandHandler("fast", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		return discord.MessageResponse("fast"), nil
+	})
+	handler.RegisterApplicationCommandHandler("slow", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Context().Done():
+			t.Error("expected context not to be cancelled after deferring")
+		}
+		return discord.MessageResponse("slow"), nil
+	})
+
+	t.Run("responds directly when the handler is fast", func(t *testing.T) {
+		var response discord.InteractionResponse
+		if err := json.NewDecoder(serveCommand(t, handler, "fast").Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+
+		if response.Type != discord.InteractionResponseTypeChannelMessageWithSource {
+			t.Errorf("expected response type %d, got %d", discord.InteractionResponseTypeChannelMessageWithSource, response.Type)
+		}
+	})
+
+	t.Run("defers when the handler is slow", func(t *testing.T) {
+		var response discord.InteractionResponse
+		if err := json.NewDecoder(serveCommand(t, handler, "slow").Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+
+		if response.Type != discord.InteractionResponseTypeDeferredChannelMessageWithSource {
+			t.Errorf("expected response type %d, got %d", discord.InteractionResponseTypeDeferredChannelMessageWithSource, response.Type)
+		}
+
+		select {
+		case content := <-edited:
+			if content != "slow" {
+				t.Errorf("expected edited content %s, got %s", "slow", content)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected original response to be edited")
+		}
+	})
+}
+
+func TestAutoDeferWithDefaultFlags(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		data   discord.InteractionResponseData
+	}
+	requests := make(chan request, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.Path}
+		if r.Method != http.MethodDelete {
+			if err := json.NewDecoder(r.Body).Decode(&req.data); err != nil {
+				t.Error(err)
+			}
+			w.Write([]byte(`{"id": "1"}`))
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		requests <- req
+	}))
+	defer server.Close()
+
+	handler := discord.NewInteractionsHandler(nil)
+	handler.Validator = &passingValidator{}
+	serverURL, _ := url.Parse(server.URL)
+	handler.Client.BaseURL = serverURL
+
+	handler.Use(
+		discord.MapErrors(func(err error) string { return "mapped: " + err.Error() }),
+		discord.AutoDefer(discord.ResponseWindow-10*time.Millisecond),
+	)
+
+	slow := func(res *discord.InteractionResponse, err error) discord.ApplicationCommandHandlerFunc {
+		return func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+			time.Sleep(100 * time.Millisecond)
+			return res, err
+		}
+	}
+	ephemeral := discord.WithDefaultFlags(discord.MessageFlagEphemeral)
+	handler.RegisterApplicationCommandHandler("secret", slow(discord.MessageResponse("secret"), nil), ephemeral)
+	handler.RegisterApplicationCommandHandler("secret-error", slow(nil, errors.New("oops")), ephemeral)
+	handler.RegisterApplicationCommandHandler("public-error", slow(nil, errors.New("oops")))
+	handler.RegisterApplicationCommandHandler("empty", slow(nil, nil))
+
+	receive := func(t *testing.T) request {
+		t.Helper()
+		select {
+		case req := <-requests:
+			return req
+		case <-time.After(time.Second):
+			t.Fatal("expected a request to Discord")
+		}
+		return request{}
+	}
+
+	tt := []struct {
+		name          string
+		command       string
+		deferredFlags discord.MessageFlags
+		want          []request
+	}{
+		{
+			name:          "result is edited into an ephemeral deferral",
+			command:       "secret",
+			deferredFlags: discord.MessageFlagEphemeral,
+			want: []request{
+				{method: http.MethodPatch, path: "/webhooks/app/token/messages/@original", data: discord.InteractionResponseData{Content: discord.String("secret"), Flags: discord.MessageFlagEphemeral}},
+			},
+		},
+		{
+			name:          "error is edited into an ephemeral deferral",
+			command:       "secret-error",
+			deferredFlags: discord.MessageFlagEphemeral,
+			want: []request{
+				{method: http.MethodPatch, path: "/webhooks/app/token/messages/@original", data: discord.InteractionResponseData{Content: discord.String("mapped: oops"), Flags: discord.MessageFlagEphemeral}},
+			},
+		},
+		{
+			name:    "error replaces a public deferral with an ephemeral follow-up",
+			command: "public-error",
+			want: []request{
+				{method: http.MethodDelete, path: "/webhooks/app/token/messages/@original"},
+				{method: http.MethodPost, path: "/webhooks/app/token", data: discord.InteractionResponseData{Content: discord.String("mapped: oops"), Flags: discord.MessageFlagEphemeral}},
+			},
+		},
+		{
+			name:    "missing response replaces a public deferral with an ephemeral follow-up",
+			command: "empty",
+			want: []request{
+				{method: http.MethodDelete, path: "/webhooks/app/token/messages/@original"},
+				{method: http.MethodPost, path: "/webhooks/app/token", data: discord.InteractionResponseData{Content: discord.String("mapped: handler returned no response"), Flags: discord.MessageFlagEphemeral}},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(&discord.Interaction{
+				Type:          discord.InteractionTypeApplicationCommand,
+				ApplicationId: "app",
+				Token:         "token",
+				Data:          &discord.ApplicationCommandInteractionData{Id: "1234567890", Name: tc.command},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewReader(b)))
+
+			var response discord.InteractionResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Type != discord.InteractionResponseTypeDeferredChannelMessageWithSource {
+				t.Fatalf("expected response type %d, got %d", discord.InteractionResponseTypeDeferredChannelMessageWithSource, response.Type)
+			}
+			var flags discord.MessageFlags
+			if response.Data != nil {
+				flags = response.Data.Flags
+			}
+			if flags != tc.deferredFlags {
+				t.Errorf("expected deferred response flags %d, got %d", tc.deferredFlags, flags)
+			}
+
+			for _, want := range tc.want {
+				got := receive(t)
+				if got.method != want.method || got.path != want.path {
+					t.Errorf("expected request %s %s, got %s %s", want.method, want.path, got.method, got.path)
+				}
+				if !reflect.DeepEqual(got.data.Content, want.data.Content) || got.data.Flags != want.data.Flags {
+					t.Errorf("expected content %q with flags %d, got %q with flags %d", contentOf(&want.data), want.data.Flags, contentOf(&got.data), got.data.Flags)
+				}
+			}
+		})
+	}
+}
+
+func contentOf(data *discord.InteractionResponseData) string {
+	if data.Content == nil {
+		return ""
+	}
+	return *data.Content
+}
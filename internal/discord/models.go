@@ -0,0 +1,129 @@
+package discord
+
+import "time"
+
+type User struct {
+	Id            string  `json:"id"`
+	Username      string  `json:"username"`
+	Discriminator string  `json:"discriminator"`
+	GlobalName    *string `json:"global_name,omitempty"`
+	Avatar        *string `json:"avatar,omitempty"`
+	Bot           bool    `json:"bot,omitempty"`
+	System        bool    `json:"system,omitempty"`
+	Banner        *string `json:"banner,omitempty"`
+	AccentColor   *int    `json:"accent_color,omitempty"`
+	Locale        string  `json:"locale,omitempty"`
+	PublicFlags   int     `json:"public_flags,omitempty"`
+}
+
+// DisplayName returns the name shown for the user in Discord.
+func (u *User) DisplayName() string {
+	if u.GlobalName != nil && *u.GlobalName != "" {
+		return *u.GlobalName
+	}
+	return u.Username
+}
+
+// Member is a user's membership of a guild.
+type Member struct {
+	// User is not included for members in resolved data.
+	User                       *User      `json:"user,omitempty"`
+	Nick                       *string    `json:"nick,omitempty"`
+	Avatar                     *string    `json:"avatar,omitempty"`
+	Roles                      []string   `json:"roles"`
+	JoinedAt                   time.Time  `json:"joined_at"`
+	PremiumSince               *time.Time `json:"premium_since,omitempty"`
+	Deaf                       bool       `json:"deaf"`
+	Mute                       bool       `json:"mute"`
+	Pending                    bool       `json:"pending,omitempty"`
+	CommunicationDisabledUntil *time.Time `json:"communication_disabled_until,omitempty"`
+	// Permissions is the member's permissions in the channel, including overwrites.
+	// It is only sent for members received in interactions.
+	Permissions Permissions `json:"permissions,omitempty"`
+}
+
+const (
+	ChannelTypeGuildText          = 0
+	ChannelTypeDM                 = 1
+	ChannelTypeGuildVoice         = 2
+	ChannelTypeGroupDM            = 3
+	ChannelTypeGuildCategory      = 4
+	ChannelTypeGuildAnnouncement  = 5
+	ChannelTypeAnnouncementThread = 10
+	ChannelTypePublicThread       = 11
+	ChannelTypePrivateThread      = 12
+	ChannelTypeGuildStageVoice    = 13
+	ChannelTypeGuildDirectory     = 14
+	ChannelTypeGuildForum         = 15
+)
+
+type Channel struct {
+	Id       string  `json:"id"`
+	Type     int     `json:"type"`
+	GuildId  string  `json:"guild_id,omitempty"`
+	Position int     `json:"position,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Topic    *string `json:"topic,omitempty"`
+	NSFW     bool    `json:"nsfw,omitempty"`
+	ParentId *string `json:"parent_id,omitempty"`
+	// Permissions is the invoking user's permissions in the channel, including overwrites.
+	// It is only sent for channels in resolved data.
+	Permissions Permissions `json:"permissions,omitempty"`
+}
+
+// Guild is a Discord server.
+// Interactions only include a partial guild, with the ID, locale and features.
+type Guild struct {
+	Id              string   `json:"id"`
+	Name            string   `json:"name,omitempty"`
+	Icon            *string  `json:"icon,omitempty"`
+	OwnerId         string   `json:"owner_id,omitempty"`
+	Features        []string `json:"features"`
+	PreferredLocale string   `json:"preferred_locale,omitempty"`
+	Locale          string   `json:"locale,omitempty"`
+}
+
+type Role struct {
+	Id           string      `json:"id"`
+	Name         string      `json:"name"`
+	Color        int         `json:"color"`
+	Hoist        bool        `json:"hoist"`
+	Icon         *string     `json:"icon,omitempty"`
+	UnicodeEmoji *string     `json:"unicode_emoji,omitempty"`
+	Position     int         `json:"position"`
+	Permissions  Permissions `json:"permissions"`
+	Managed      bool        `json:"managed"`
+	Mentionable  bool        `json:"mentionable"`
+}
+
+type Message struct {
+	Id              string        `json:"id"`
+	ChannelId       string        `json:"channel_id"`
+	Author          *User         `json:"author,omitempty"`
+	Content         string        `json:"content"`
+	Timestamp       time.Time     `json:"timestamp"`
+	EditedTimestamp *time.Time    `json:"edited_timestamp,omitempty"`
+	TTS             bool          `json:"tts"`
+	MentionEveryone bool          `json:"mention_everyone"`
+	Mentions        []*User       `json:"mentions"`
+	MentionRoles    []string      `json:"mention_roles"`
+	Attachments     []*Attachment `json:"attachments"`
+	Embeds          []*Embed      `json:"embeds"`
+	Pinned          bool          `json:"pinned"`
+	Type            int           `json:"type"`
+	Flags           MessageFlags  `json:"flags,omitempty"`
+	Components      []ActionRow   `json:"components,omitempty"`
+}
+
+type Attachment struct {
+	Id          string  `json:"id"`
+	Filename    string  `json:"filename"`
+	Description *string `json:"description,omitempty"`
+	ContentType *string `json:"content_type,omitempty"`
+	Size        int     `json:"size"`
+	URL         string  `json:"url"`
+	ProxyURL    string  `json:"proxy_url"`
+	Height      *int    `json:"height,omitempty"`
+	Width       *int    `json:"width,omitempty"`
+	Ephemeral   bool    `json:"ephemeral,omitempty"`
+}
@@ -0,0 +1,106 @@
+package discord
+
+import "math"
+
+// Options returns the options the command was invoked with.
+// If a subcommand was invoked, the options of the subcommand are returned instead.
+func (ctx *InteractionContext) Options() []ApplicationCommandInteractionDataOption {
+	options := ctx.Interaction.ApplicationCommandData().Options
+	for len(options) == 1 {
+		t := options[0].Type
+		if t != ApplicationCommandOptionTypeSubCommand && t != ApplicationCommandOptionTypeSubCommandGroup {
+			break
+		}
+		options = options[0].Options
+	}
+	return options
+}
+
+// Option returns the option with the given name, if it was provided.
+func (ctx *InteractionContext) Option(name string) (*ApplicationCommandInteractionDataOption, bool) {
+	options := ctx.Options()
+	for i := range options {
+		if options[i].Name == name {
+			return &options[i], true
+		}
+	}
+	return nil, false
+}
+
+// StringOption returns the value of a string option.
+// ok is false if the option was not provided or is not a string.
+func (ctx *InteractionContext) StringOption(name string) (value string, ok bool) {
+	o, ok := ctx.Option(name)
+	if !ok {
+		return "", false
+	}
+	value, ok = o.Value.(string)
+	return value, ok
+}
+
+// IntOption returns the value of an integer option.
+// ok is false if the option was not provided or is not a whole number.
+func (ctx *InteractionContext) IntOption(name string) (value int, ok bool) {
+	f, ok := ctx.NumberOption(name)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// NumberOption returns the value of a number option.
+// ok is false if the option was not provided or is not a number.
+func (ctx *InteractionContext) NumberOption(name string) (value float64, ok bool) {
+	o, ok := ctx.Option(name)
+	if !ok {
+		return 0, false
+	}
+	// JSON numbers are always decoded as float64.
+	value, ok = o.Value.(float64)
+	return value, ok
+}
+
+// BoolOption returns the value of a boolean option.
+// ok is false if the option was not provided or is not a boolean.
+func (ctx *InteractionContext) BoolOption(name string) (value bool, ok bool) {
+	o, ok := ctx.Option(name)
+	if !ok {
+		return false, false
+	}
+	value, ok = o.Value.(bool)
+	return value, ok
+}
+
+// UserOption returns the user selected for a user option.
+// ok is false if the option was not provided or the user is missing from the resolved data.
+func (ctx *InteractionContext) UserOption(name string) (user *User, ok bool) {
+	id, ok := ctx.StringOption(name)
+	if !ok {
+		return nil, false
+	}
+
+	resolved := ctx.Interaction.ApplicationCommandData().Resolved
+	if resolved == nil {
+		return nil, false
+	}
+
+	user, ok = resolved.Users[id]
+	return user, ok
+}
+
+// AttachmentOption returns the file uploaded for an attachment option.
+// ok is false if the option was not provided or the attachment is missing from the resolved data.
+func (ctx *InteractionContext) AttachmentOption(name string) (attachment *Attachment, ok bool) {
+	id, ok := ctx.StringOption(name)
+	if !ok {
+		return nil, false
+	}
+
+	resolved := ctx.Interaction.ApplicationCommandData().Resolved
+	if resolved == nil {
+		return nil, false
+	}
+
+	attachment, ok = resolved.Attachments[id]
+	return attachment, ok
+}
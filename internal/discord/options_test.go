@@ -0,0 +1,95 @@
+package discord_test
+
+import (
+	"testing"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func newCommandContext(data *discord.ApplicationCommandInteractionData) *discord.InteractionContext {
+	return &discord.InteractionContext{
+		Interaction: &discord.Interaction{
+			Type: discord.InteractionTypeApplicationCommand,
+			Data: data,
+		},
+	}
+}
+
+func TestOptionAccessors(t *testing.T) {
+	ctx := newCommandContext(&discord.ApplicationCommandInteractionData{
+		Name: "blep",
+		Options: []discord.ApplicationCommandInteractionDataOption{
+			{Name: "count", Type: discord.ApplicationCommandOptionTypeInteger, Value: float64(3)},
+			{Name: "ratio", Type: discord.ApplicationCommandOptionTypeNumber, Value: 0.5},
+			{Name: "animal", Type: discord.ApplicationCommandOptionTypeString, Value: "animal_dog"},
+			{Name: "only_smol", Type: discord.ApplicationCommandOptionTypeBoolean, Value: true},
+			{Name: "friend", Type: discord.ApplicationCommandOptionTypeUser, Value: "42"},
+			{Name: "photo", Type: discord.ApplicationCommandOptionTypeAttachment, Value: "7"},
+		},
+		Resolved: &discord.ResolvedData{
+			Users:       map[string]*discord.User{"42": {Id: "42", Username: "ghost"}},
+			Attachments: map[string]*discord.Attachment{"7": {Id: "7", Filename: "dog.png"}},
+		},
+	})
+
+	if v, ok := ctx.IntOption("count"); !ok || v != 3 {
+		t.Errorf("expected int option %d, got %d (ok=%v)", 3, v, ok)
+	}
+
+	if _, ok := ctx.IntOption("ratio"); ok {
+		t.Error("expected fractional number not to be returned as an int")
+	}
+
+	if v, ok := ctx.NumberOption("ratio"); !ok || v != 0.5 {
+		t.Errorf("expected number option %v, got %v (ok=%v)", 0.5, v, ok)
+	}
+
+	if v, ok := ctx.StringOption("animal"); !ok || v != "animal_dog" {
+		t.Errorf("expected string option %s, got %s (ok=%v)", "animal_dog", v, ok)
+	}
+
+	if v, ok := ctx.BoolOption("only_smol"); !ok || !v {
+		t.Errorf("expected bool option %v, got %v (ok=%v)", true, v, ok)
+	}
+
+	if v, ok := ctx.UserOption("friend"); !ok || v.Username != "ghost" {
+		t.Errorf("expected user option %s, got %+v (ok=%v)", "ghost", v, ok)
+	}
+
+	if v, ok := ctx.AttachmentOption("photo"); !ok || v.Filename != "dog.png" {
+		t.Errorf("expected attachment option %s, got %+v (ok=%v)", "dog.png", v, ok)
+	}
+
+	if _, ok := ctx.StringOption("count"); ok {
+		t.Error("expected mismatched option type not to be returned")
+	}
+
+	if _, ok := ctx.StringOption("missing"); ok {
+		t.Error("expected missing option not to be returned")
+	}
+}
+
+func TestOptionAccessorsDescendIntoSubcommands(t *testing.T) {
+	ctx := newCommandContext(&discord.ApplicationCommandInteractionData{
+		Name: "text",
+		Options: []discord.ApplicationCommandInteractionDataOption{
+			{
+				Name: "tools",
+				Type: discord.ApplicationCommandOptionTypeSubCommandGroup,
+				Options: []discord.ApplicationCommandInteractionDataOption{
+					{
+						Name: "shuffle",
+						Type: discord.ApplicationCommandOptionTypeSubCommand,
+						Options: []discord.ApplicationCommandInteractionDataOption{
+							{Name: "message", Type: discord.ApplicationCommandOptionTypeString, Value: "hello world"},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	if v, ok := ctx.StringOption("message"); !ok || v != "hello world" {
+		t.Errorf("expected string option %s, got %s (ok=%v)", "hello world", v, ok)
+	}
+}
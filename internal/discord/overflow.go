@@ -0,0 +1,102 @@
+package discord
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxContentLength is the maximum number of characters Discord allows in the content of a message.
+const MaxContentLength = 2000
+
+// ContentOverflow determines what happens to message content that is longer than MaxContentLength.
+type ContentOverflow int
+
+const (
+	// ContentOverflowTruncate cuts the content short, ending it with an ellipsis.
+	ContentOverflowTruncate ContentOverflow = iota
+	// ContentOverflowSplit sends the content as several messages.
+	// The first part is sent as the message itself, and the rest as follow-up messages.
+	ContentOverflowSplit
+	// ContentOverflowAttach sends the content as a text file attached to the message.
+	ContentOverflowAttach
+)
+
+const (
+	ellipsis             = "…"
+	overflowFilename     = "message.txt"
+	overflowAttachedNote = "The message was too long to send, so it has been attached as a file."
+)
+
+// fitContent makes the content of data fit within MaxContentLength, according to its Overflow strategy.
+// When the content is split, the follow-up messages holding the rest of it are returned.
+func (d *InteractionResponseData) fitContent() []*InteractionResponseData {
+	if d == nil || d.Content == nil || utf8.RuneCountInString(*d.Content) <= MaxContentLength {
+		return nil
+	}
+
+	content := *d.Content
+	switch d.Overflow {
+	case ContentOverflowSplit:
+		parts := splitContent(content, MaxContentLength)
+		d.Content = String(parts[0])
+
+		followups := make([]*InteractionResponseData, 0, len(parts)-1)
+		for _, part := range parts[1:] {
+			followups = append(followups, &InteractionResponseData{
+				TTS:             d.TTS,
+				Content:         String(part),
+				AllowedMentions: d.AllowedMentions,
+				Flags:           d.Flags,
+			})
+		}
+		return followups
+	case ContentOverflowAttach:
+		d.Content = String(overflowAttachedNote)
+		d.Files = append(d.Files, NewTextFile(overflowFilename, content))
+		return nil
+	default:
+		d.Content = String(truncateContent(content, MaxContentLength))
+		return nil
+	}
+}
+
+// truncateContent shortens s to at most limit characters, ending it with an ellipsis if anything was cut.
+func truncateContent(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+
+	runes := []rune(s)
+	return string(runes[:limit-utf8.RuneCountInString(ellipsis)]) + ellipsis
+}
+
+// splitContent splits s into parts of at most limit characters.
+// Where possible, parts are split at the last line break or space before the limit.
+func splitContent(s string, limit int) []string {
+	var parts []string
+	runes := []rune(s)
+	for len(runes) > limit {
+		end := limit
+		if i := lastIndexRune(runes[:limit], '\n'); i > 0 {
+			end = i + 1
+		} else if i := lastIndexRune(runes[:limit], ' '); i > 0 {
+			end = i + 1
+		}
+
+		if part := strings.TrimRight(string(runes[:end]), "\n "); part != "" {
+			parts = append(parts, part)
+		}
+		runes = runes[end:]
+	}
+
+	return append(parts, string(runes))
+}
+
+func lastIndexRune(runes []rune, r rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
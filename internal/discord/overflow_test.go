@@ -0,0 +1,116 @@
+package discord_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func TestContentOverflow(t *testing.T) {
+	long := strings.Repeat("word ", 500)
+
+	t.Run("truncate", func(t *testing.T) {
+		handler := discord.NewInteractionsHandler(nil)
+		handler.Validator = &passingValidator{}
+		handler.RegisterApplicationCommandHandler("test", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+			return discord.MessageResponse(long), nil
+		})
+
+		w := serveCommand(t, handler, "test")
+
+		var response discord.InteractionResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content := *response.Data.Content
+		if utf8.RuneCountInString(content) != discord.MaxContentLength {
+			t.Errorf("expected content length %d, got %d", discord.MaxContentLength, utf8.RuneCountInString(content))
+		}
+		if !strings.HasSuffix(content, "…") {
+			t.Errorf("expected content to end with an ellipsis, got %q", content[len(content)-10:])
+		}
+	})
+
+	t.Run("attach", func(t *testing.T) {
+		handler := discord.NewInteractionsHandler(nil)
+		handler.Validator = &passingValidator{}
+		handler.RegisterApplicationCommandHandler("test", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+			res := discord.MessageResponse(long)
+			res.Data.Overflow = discord.ContentOverflowAttach
+			return res, nil
+		})
+
+		w := serveCommand(t, handler, "test")
+
+		var response discord.InteractionResponse
+		files := readMultipart(t, w.Header().Get("Content-Type"), w.Body, &response)
+		if utf8.RuneCountInString(*response.Data.Content) > discord.MaxContentLength {
+			t.Errorf("expected content to fit in a message, got %d characters", utf8.RuneCountInString(*response.Data.Content))
+		}
+
+		if files["files[0]"] != "message.txt:"+long {
+			t.Errorf("expected the content to be attached as message.txt")
+		}
+	})
+
+	t.Run("split", func(t *testing.T) {
+		followups := make(chan string, 2)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var data discord.InteractionResponseData
+			err := json.NewDecoder(r.Body).Decode(&data)
+			if err != nil {
+				t.Error(err)
+			}
+			followups <- *data.Content
+			w.Write([]byte(`{"id": "1"}`))
+		}))
+		defer server.Close()
+
+		handler := discord.NewInteractionsHandler(nil)
+		handler.Validator = &passingValidator{}
+		handler.Client.BaseURL, _ = url.Parse(server.URL + "/")
+		handler.RegisterApplicationCommandHandler("test", func(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+			res := discord.MessageResponse(long)
+			res.Data.Overflow = discord.ContentOverflowSplit
+			return res, nil
+		})
+
+		w := serveCommand(t, handler, "test")
+		if !w.Flushed {
+			t.Error("expected the response to be flushed before sending follow-up messages")
+		}
+
+		var response discord.InteractionResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parts := []string{*response.Data.Content}
+		select {
+		case part := <-followups:
+			parts = append(parts, part)
+		case <-time.After(time.Second):
+			t.Fatal("expected a follow-up message with the rest of the content")
+		}
+
+		for i, part := range parts {
+			if utf8.RuneCountInString(part) > discord.MaxContentLength {
+				t.Errorf("expected part %d to fit in a message, got %d characters", i, utf8.RuneCountInString(part))
+			}
+		}
+
+		if strings.TrimSpace(strings.Join(parts, " ")) != strings.TrimSpace(long) {
+			t.Errorf("expected the parts to hold all of the content")
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Permissions is a set of Discord permissions.
+// Discord sends permissions as a bit set in a string, since it can be larger than a JSON number can safely hold.
+type Permissions uint64
+
+const (
+	PermissionCreateInstantInvite Permissions = 1 << iota
+	PermissionKickMembers
+	PermissionBanMembers
+	PermissionAdministrator
+	PermissionManageChannels
+	PermissionManageGuild
+	PermissionAddReactions
+	PermissionViewAuditLog
+	PermissionPrioritySpeaker
+	PermissionStream
+	PermissionViewChannel
+	PermissionSendMessages
+	PermissionSendTTSMessages
+	PermissionManageMessages
+	PermissionEmbedLinks
+	PermissionAttachFiles
+	PermissionReadMessageHistory
+	PermissionMentionEveryone
+	PermissionUseExternalEmojis
+	PermissionViewGuildInsights
+	PermissionConnect
+	PermissionSpeak
+	PermissionMuteMembers
+	PermissionDeafenMembers
+	PermissionMoveMembers
+	PermissionUseVAD
+	PermissionChangeNickname
+	PermissionManageNicknames
+	PermissionManageRoles
+	PermissionManageWebhooks
+	PermissionManageGuildExpressions
+	PermissionUseApplicationCommands
+	PermissionRequestToSpeak
+	PermissionManageEvents
+	PermissionManageThreads
+	PermissionCreatePublicThreads
+	PermissionCreatePrivateThreads
+	PermissionUseExternalStickers
+	PermissionSendMessagesInThreads
+	PermissionUseEmbeddedActivities
+	PermissionModerateMembers
+)
+
+// permissionNames are the names Discord uses for each permission, in bit order.
+var permissionNames = []string{
+	"CREATE_INSTANT_INVITE",
+	"KICK_MEMBERS",
+	"BAN_MEMBERS",
+	"ADMINISTRATOR",
+	"MANAGE_CHANNELS",
+	"MANAGE_GUILD",
+	"ADD_REACTIONS",
+	"VIEW_AUDIT_LOG",
+	"PRIORITY_SPEAKER",
+	"STREAM",
+	"VIEW_CHANNEL",
+	"SEND_MESSAGES",
+	"SEND_TTS_MESSAGES",
+	"MANAGE_MESSAGES",
+	"EMBED_LINKS",
+	"ATTACH_FILES",
+	"READ_MESSAGE_HISTORY",
+	"MENTION_EVERYONE",
+	"USE_EXTERNAL_EMOJIS",
+	"VIEW_GUILD_INSIGHTS",
+	"CONNECT",
+	"SPEAK",
+	"MUTE_MEMBERS",
+	"DEAFEN_MEMBERS",
+	"MOVE_MEMBERS",
+	"USE_VAD",
+	"CHANGE_NICKNAME",
+	"MANAGE_NICKNAMES",
+	"MANAGE_ROLES",
+	"MANAGE_WEBHOOKS",
+	"MANAGE_GUILD_EXPRESSIONS",
+	"USE_APPLICATION_COMMANDS",
+	"REQUEST_TO_SPEAK",
+	"MANAGE_EVENTS",
+	"MANAGE_THREADS",
+	"CREATE_PUBLIC_THREADS",
+	"CREATE_PRIVATE_THREADS",
+	"USE_EXTERNAL_STICKERS",
+	"SEND_MESSAGES_IN_THREADS",
+	"USE_EMBEDDED_ACTIVITIES",
+	"MODERATE_MEMBERS",
+}
+
+// NewPermissions returns a pointer to the set of the given permissions,
+// for setting DefaultMemberPermissions. With no permissions, the command is only available to administrators.
+func NewPermissions(permissions ...Permissions) *Permissions {
+	var p Permissions
+	for _, permission := range permissions {
+		p |= permission
+	}
+	return &p
+}
+
+// ParsePermissions parses a set of permissions written by hand, such as in the application commands spec file.
+// It accepts either a bit set in a decimal string as Discord sends them,
+// or permission names separated by "|", e.g. "MANAGE_GUILD|KICK_MEMBERS".
+func ParsePermissions(s string) (Permissions, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("discord: empty permissions")
+	}
+
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return Permissions(n), nil
+	}
+
+	var p Permissions
+	for _, name := range strings.Split(s, "|") {
+		permission, ok := permissionByName(strings.TrimSpace(name))
+		if !ok {
+			return 0, fmt.Errorf("discord: unknown permission %q", name)
+		}
+		p |= permission
+	}
+	return p, nil
+}
+
+func permissionByName(name string) (Permissions, bool) {
+	for i, permissionName := range permissionNames {
+		if strings.EqualFold(name, permissionName) {
+			return 1 << i, true
+		}
+	}
+	return 0, false
+}
+
+// Has reports whether p includes all of the given permissions.
+// Administrator implies every permission.
+func (p Permissions) Has(permissions Permissions) bool {
+	return p&PermissionAdministrator != 0 || p&permissions == permissions
+}
+
+// Names returns the names of the permissions in p, in bit order.
+// Unknown bits are returned as their decimal value.
+func (p Permissions) Names() []string {
+	var names []string
+	for i := 0; i < 64; i++ {
+		bit := Permissions(1) << i
+		if p&bit == 0 {
+			continue
+		}
+		if i < len(permissionNames) {
+			names = append(names, permissionNames[i])
+		} else {
+			names = append(names, strconv.FormatUint(uint64(bit), 10))
+		}
+	}
+	return names
+}
+
+// String returns the permissions as a decimal bit set, as Discord sends them.
+func (p Permissions) String() string {
+	return strconv.FormatUint(uint64(p), 10)
+}
+
+func (p Permissions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON accepts permissions as a decimal string, as Discord sends them, or as a number.
+// An empty string is no permissions. Permission names are not accepted, see ParsePermissions.
+func (p *Permissions) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n uint64
+		if json.Unmarshal(data, &n) != nil {
+			return fmt.Errorf("discord: permissions must be a string, got %s", data)
+		}
+		*p = Permissions(n)
+		return nil
+	}
+
+	if s == "" {
+		*p = 0
+		return nil
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("discord: invalid permissions %q", s)
+	}
+	*p = Permissions(n)
+	return nil
+}
@@ -0,0 +1,114 @@
+package discord_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func TestParsePermissions(t *testing.T) {
+	tt := []struct {
+		input   string
+		want    discord.Permissions
+		wantErr bool
+	}{
+		{input: "0", want: 0},
+		{input: "8", want: discord.PermissionAdministrator},
+		{input: "1099511627776", want: discord.PermissionModerateMembers},
+		{input: "ADMINISTRATOR", want: discord.PermissionAdministrator},
+		{input: "manage_guild | KICK_MEMBERS", want: discord.PermissionManageGuild | discord.PermissionKickMembers},
+		{input: "", wantErr: true},
+		{input: "ADMIN", wantErr: true},
+		{input: "-8", wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := discord.ParsePermissions(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	p := discord.PermissionSendMessages | discord.PermissionEmbedLinks
+
+	if !p.Has(discord.PermissionSendMessages) {
+		t.Errorf("expected %s to have SEND_MESSAGES", p)
+	}
+	if p.Has(discord.PermissionSendMessages | discord.PermissionAttachFiles) {
+		t.Errorf("expected %s not to have ATTACH_FILES", p)
+	}
+	if !discord.PermissionAdministrator.Has(discord.PermissionBanMembers) {
+		t.Errorf("expected administrator to imply every permission")
+	}
+
+	if names := p.Names(); !reflect.DeepEqual(names, []string{"SEND_MESSAGES", "EMBED_LINKS"}) {
+		t.Errorf("expected names [SEND_MESSAGES EMBED_LINKS], got %v", names)
+	}
+}
+
+func TestPermissionsJSON(t *testing.T) {
+	var command discord.RegisterApplicationCommandOptions
+	err := json.Unmarshal([]byte(`{"name":"ban","default_member_permissions":"6","dm_permission":false,"nsfw":true}`), &command)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := discord.PermissionBanMembers | discord.PermissionKickMembers
+	if command.DefaultMemberPermissions == nil || *command.DefaultMemberPermissions != want {
+		t.Errorf("expected default member permissions %s, got %v", want, command.DefaultMemberPermissions)
+	}
+
+	b, err := json.Marshal(command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"name":"ban","default_member_permissions":"6","dm_permission":false,"nsfw":true}`; string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+
+	tt := []struct {
+		input   string
+		want    discord.Permissions
+		wantErr bool
+	}{
+		{input: `"2048"`, want: discord.PermissionSendMessages},
+		{input: `2048`, want: discord.PermissionSendMessages},
+		{input: `""`, want: 0},
+		{input: `"SEND_MESSAGES"`, wantErr: true},
+		{input: `true`, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.input, func(t *testing.T) {
+			var member discord.Member
+			err := json.Unmarshal([]byte(`{"roles":[],"joined_at":"2022-01-01T00:00:00Z","deaf":false,"mute":false,"permissions":`+tc.input+`}`), &member)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got permissions %s", member.Permissions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if member.Permissions != tc.want {
+				t.Errorf("expected member permissions %s, got %s", tc.want, member.Permissions)
+			}
+		})
+	}
+}
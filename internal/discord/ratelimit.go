@@ -0,0 +1,175 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucket tracks the rate limit shared by one or more routes.
+type bucket struct {
+	remaining int
+	reset     time.Time
+}
+
+// rateLimiter follows the rate limits Discord reports in the X-RateLimit-* response headers.
+// Routes are mapped to buckets by the X-RateLimit-Bucket header, as several routes can share a bucket.
+// The header does not include the route's major parameter, which Discord limits separately, so buckets are
+// keyed by both.
+type rateLimiter struct {
+	mu      sync.Mutex
+	routes  map[string]string
+	buckets map[string]*bucket
+	global  time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		routes:  make(map[string]string),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// maxTrackedRoutes is how many routes are tracked before those with expired limits are forgotten.
+// Interaction webhook routes include the token, so a new route is seen for every interaction.
+const maxTrackedRoutes = 1000
+
+// wait blocks until a request can be made to the route without exceeding its rate limit or the global one.
+func (l *rateLimiter) wait(ctx context.Context, route string) error {
+	for {
+		delay := l.reserve(route)
+		if delay <= 0 {
+			return nil
+		}
+
+		err := sleep(ctx, delay)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a request from the route's bucket, or returns how long to wait before trying again.
+func (l *rateLimiter) reserve(route string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.global) {
+		return l.global.Sub(now)
+	}
+
+	b, ok := l.buckets[l.routes[route]]
+	if !ok || now.After(b.reset) {
+		return 0
+	}
+
+	if b.remaining <= 0 {
+		return b.reset.Sub(now)
+	}
+
+	b.remaining--
+	return 0
+}
+
+// update records the rate limit reported in the headers of a response from the route.
+func (l *rateLimiter) update(route string, header http.Header) {
+	hash := header.Get("X-RateLimit-Bucket")
+	if hash == "" {
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	resetAfter, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.routes) >= maxTrackedRoutes {
+		l.prune()
+	}
+
+	key := hash + " " + majorParameter(route)
+	l.routes[route] = key
+	l.buckets[key] = &bucket{
+		remaining: remaining,
+		reset:     time.Now().Add(seconds(resetAfter)),
+	}
+}
+
+// limitGlobally blocks all requests for the given duration.
+func (l *rateLimiter) limitGlobally(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if reset := time.Now().Add(d); reset.After(l.global) {
+		l.global = reset
+	}
+}
+
+// prune forgets buckets whose limits have reset, along with the routes that use them.
+func (l *rateLimiter) prune() {
+	now := time.Now()
+	for key, b := range l.buckets {
+		if now.After(b.reset) {
+			delete(l.buckets, key)
+		}
+	}
+	for route, key := range l.routes {
+		if _, ok := l.buckets[key]; !ok {
+			delete(l.routes, route)
+		}
+	}
+}
+
+// majorParameter returns the top-level resource in the route's path: a channel, a guild,
+// or a webhook or interaction with its token. Routes without one return an empty string.
+func majorParameter(route string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		n := 0
+		switch segment {
+		case "channels", "guilds":
+			n = 1
+		case "webhooks", "interactions":
+			n = 2
+		default:
+			continue
+		}
+
+		end := i + 1 + n
+		if end > len(segments) {
+			end = len(segments)
+		}
+		return strings.Join(segments[i:end], "/")
+	}
+	return ""
+}
+
+// seconds converts a number of seconds, as used in rate limit headers and bodies, to a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// sleep waits for the given duration, returning early with an error if the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,171 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// maxRetries is how many times a request is retried after a rate limit or server error.
+	maxRetries = 3
+	// retryBaseDelay is how long to wait before retrying a request after a server error.
+	// It doubles with each attempt.
+	retryBaseDelay = 500 * time.Millisecond
+)
+
+// rateLimitResponse is the body of a 429 Too Many Requests response.
+type rateLimitResponse struct {
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+// do sends a request to the API path, encoding body as JSON and decoding the response into out.
+// A nil body sends no body, and message data with files is sent as a multipart form.
+// It waits for rate limits, and retries rate limited requests and server errors.
+// If the response has an error status code an *APIError is returned.
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	route := method + " " + req.URL.Path
+	for attempt := 0; ; attempt++ {
+		err := c.rateLimiter.wait(ctx, route)
+		if err != nil {
+			return err
+		}
+
+		if attempt > 0 && req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return err
+			}
+		}
+
+		res, err := c.client.Do(req)
+		if err != nil {
+			return err
+		}
+
+		c.rateLimiter.update(route, res.Header)
+
+		retry, delay, err := c.checkResponse(res, attempt, attempt < maxRetries)
+		if retry {
+			res.Body.Close()
+			err = sleep(ctx, delay)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			res.Body.Close()
+			return err
+		}
+
+		err = decodeResponse(res, out)
+		res.Body.Close()
+		return err
+	}
+}
+
+// newRequest creates a request to the API path with the given body.
+func (c *Client) newRequest(ctx context.Context, method string, path string, body interface{}) (*http.Request, error) {
+	u, err := c.BaseURL.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	contentType := ""
+	switch body := body.(type) {
+	case nil:
+	case *InteractionResponseData:
+		buf, contentType, err = encodeMessageBody(body)
+	default:
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		err = enc.Encode(body)
+		contentType = "application/json"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	// interaction webhooks are authenticated by the token in the path instead.
+	if c.botToken != "" && !strings.HasPrefix(path, "webhooks/") {
+		req.Header.Set("Authorization", fmt.Sprintf("Bot %s", c.botToken))
+	}
+
+	return req, nil
+}
+
+// checkResponse returns whether a response should be retried and how long to wait first,
+// or an *APIError if it has an error status code that should not be retried.
+func (c *Client) checkResponse(res *http.Response, attempt int, canRetry bool) (bool, time.Duration, error) {
+	if res.StatusCode < http.StatusBadRequest {
+		return false, 0, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		var limit rateLimitResponse
+		if json.Unmarshal(body, &limit) == nil {
+			delay := seconds(limit.RetryAfter)
+			if limit.Global || res.Header.Get("X-RateLimit-Global") == "true" {
+				c.rateLimiter.limitGlobally(delay)
+			}
+			if canRetry {
+				return true, delay, nil
+			}
+		}
+	} else if res.StatusCode >= http.StatusInternalServerError && canRetry {
+		return true, retryBaseDelay << attempt, nil
+	}
+
+	apiErr := &APIError{StatusCode: res.StatusCode}
+	if json.Unmarshal(body, apiErr) != nil && len(body) > 0 {
+		apiErr.Message = string(body)
+	}
+	return false, 0, apiErr
+}
+
+// decodeResponse decodes the JSON body of a response into v, ignoring empty bodies.
+func decodeResponse(res *http.Response, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	err := json.NewDecoder(res.Body).Decode(v)
+	if err == io.EOF {
+		// ignore EOF errors caused by empty response body
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+package discord_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *discord.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return discord.NewClient("token", discord.WithBaseURL(server.URL))
+}
+
+func TestAPIError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{
+			"code": 50035,
+			"message": "Invalid Form Body",
+			"errors": {
+				"name": {"_errors": [{"code": "BASE_TYPE_REQUIRED", "message": "This field is required"}]},
+				"options": {
+					"0": {"description": {"_errors": [{"code": "BASE_TYPE_BAD_LENGTH", "message": "Must be between 1 and 100 in length."}]}}
+				}
+			}
+		}`))
+	})
+
+	_, err := client.ApplicationCommands.Register(context.Background(), "1234567890", &discord.RegisterApplicationCommandOptions{})
+
+	var apiErr *discord.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *APIError, got %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, apiErr.StatusCode)
+	}
+
+	if apiErr.Code != 50035 {
+		t.Errorf("expected code %d, got %d", 50035, apiErr.Code)
+	}
+
+	fieldErrs := apiErr.FieldErrors()
+	if len(fieldErrs) != 2 {
+		t.Fatalf("expected %d field errors, got %d", 2, len(fieldErrs))
+	}
+
+	expected := []discord.FieldError{
+		{Path: "name", Code: "BASE_TYPE_REQUIRED", Message: "This field is required"},
+		{Path: "options.0.description", Code: "BASE_TYPE_BAD_LENGTH", Message: "Must be between 1 and 100 in length."},
+	}
+	for i, fieldErr := range fieldErrs {
+		if fieldErr != expected[i] {
+			t.Errorf("expected field error %v, got %v", expected[i], fieldErr)
+		}
+	}
+
+	want := "discord: 400 Bad Request: Invalid Form Body (50035); name: This field is required; options.0.description: Must be between 1 and 100 in length."
+	if err.Error() != want {
+		t.Errorf("expected error %q, got %q", want, err.Error())
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tt := []struct {
+		name    string
+		fail    func(w http.ResponseWriter)
+		success bool
+	}{
+		{
+			name: "rate limited",
+			fail: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.01, "global": false}`))
+			},
+			success: true,
+		},
+		{
+			name: "globally rate limited",
+			fail: func(w http.ResponseWriter) {
+				w.Header().Set("X-RateLimit-Global", "true")
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.01, "global": true}`))
+			},
+			success: true,
+		},
+		{
+			name: "server error",
+			fail: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			success: true,
+		},
+		{
+			name: "client error",
+			fail: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"code": 50001, "message": "Missing Access"}`))
+			},
+			success: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var bodies []string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Error(err)
+				}
+				bodies = append(bodies, string(b))
+
+				if len(bodies) == 1 {
+					tc.fail(w)
+					return
+				}
+				w.Write([]byte(`{"id": "1", "name": "test"}`))
+			})
+
+			command, err := client.ApplicationCommands.Register(context.Background(), "1234567890", &discord.RegisterApplicationCommandOptions{
+				Name: "test",
+			})
+
+			if !tc.success {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				if len(bodies) != 1 {
+					t.Errorf("expected %d request, got %d", 1, len(bodies))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if command.Id != "1" {
+				t.Errorf("expected command ID %s, got %s", "1", command.Id)
+			}
+
+			if len(bodies) != 2 {
+				t.Fatalf("expected %d requests, got %d", 2, len(bodies))
+			}
+
+			if bodies[0] != bodies[1] {
+				t.Errorf("expected the retried request to have the same body, got %q and %q", bodies[0], bodies[1])
+			}
+		})
+	}
+}
+
+func TestRateLimitBucket(t *testing.T) {
+	var times []time.Time
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+
+		w.Header().Set("X-RateLimit-Bucket", "abcd")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset-After", "0.1")
+		w.Write([]byte(`[]`))
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.ApplicationCommands.BulkOverwrite(context.Background(), "1234567890", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(times) != 2 {
+		t.Fatalf("expected %d requests, got %d", 2, len(times))
+	}
+
+	if d := times[1].Sub(times[0]); d < 90*time.Millisecond {
+		t.Errorf("expected the second request to wait for the bucket to reset, it was sent after %s", d)
+	}
+}
+
+func TestRateLimitBucketPerMajorParameter(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		remaining := "4"
+		if strings.HasSuffix(r.URL.Path, "/token-b") {
+			remaining = "0"
+		}
+
+		w.Header().Set("X-RateLimit-Bucket", "abcd")
+		w.Header().Set("X-RateLimit-Remaining", remaining)
+		w.Header().Set("X-RateLimit-Reset-After", "1")
+		w.Write([]byte(`{"id": "1"}`))
+	})
+
+	for _, token := range []string{"token-a", "token-b"} {
+		_, err := client.InteractionWebhooks.CreateFollowupMessage(context.Background(), "1234567890", token, &discord.InteractionResponseData{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now()
+	_, err := client.InteractionWebhooks.CreateFollowupMessage(context.Background(), "1234567890", "token-a", &discord.InteractionResponseData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("expected token-a to have its own bucket, its request waited %s for token-b's bucket to reset", d)
+	}
+}
+
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(`{"id": "1"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestClientOptions(t *testing.T) {
+	transport := &recordingTransport{}
+	client := discord.NewClient("token",
+		discord.WithTransport(transport),
+		discord.WithUserAgent("test-agent"),
+		discord.WithBaseURL("http://discord.test/api"),
+	)
+
+	_, err := client.ApplicationCommands.Register(context.Background(), "1234567890", &discord.RegisterApplicationCommandOptions{Name: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.InteractionWebhooks.CreateFollowupMessage(context.Background(), "1234567890", "token", &discord.InteractionResponseData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transport.requests) != 2 {
+		t.Fatalf("expected %d requests, got %d", 2, len(transport.requests))
+	}
+
+	tt := []struct {
+		url           string
+		authorization string
+	}{
+		{url: "http://discord.test/api/applications/1234567890/commands", authorization: "Bot token"},
+		{url: "http://discord.test/api/webhooks/1234567890/token", authorization: ""},
+	}
+
+	for i, tc := range tt {
+		req := transport.requests[i]
+		if req.URL.String() != tc.url {
+			t.Errorf("expected request URL %s, got %s", tc.url, req.URL)
+		}
+
+		if req.Header.Get("User-Agent") != "test-agent" {
+			t.Errorf("expected User-Agent %s, got %s", "test-agent", req.Header.Get("User-Agent"))
+		}
+
+		if req.Header.Get("Authorization") != tc.authorization {
+			t.Errorf("expected Authorization %q, got %q", tc.authorization, req.Header.Get("Authorization"))
+		}
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 10}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ApplicationCommands.BulkOverwrite(ctx, "1234567890", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,307 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits that Discord places on application commands and their options.
+const (
+	MaxCommandOptions    = 25
+	MaxOptionChoices     = 25
+	MaxCommandNameLength = 32
+	MaxDescriptionLength = 100
+	MaxChoiceNameLength  = 100
+	MaxChoiceValueLength = 100
+	MaxOptionLengthLimit = 6000
+)
+
+// Validate checks the command against the rules that Discord enforces when registering it,
+// returning an error describing every problem found.
+func (o *RegisterApplicationCommandOptions) Validate() error {
+	commandType := ApplicationCommandTypeChatInput
+	if o.Type != nil {
+		commandType = *o.Type
+	}
+
+	var errs []error
+	if commandType == ApplicationCommandTypeChatInput {
+		errs = append(errs, validateChatInputName("name", o.Name)...)
+		errs = append(errs, validateLocalizations("name_localizations", o.NameLocalizations, validateChatInputName)...)
+		errs = append(errs, validateDescription("description", stringValue(o.Description))...)
+		errs = append(errs, validateLocalizations("description_localizations", o.DescriptionLocalizations, validateDescription)...)
+		errs = append(errs, validateOptionList("options", o.Options, 0)...)
+	} else {
+		errs = append(errs, validateLength("name", o.Name, 1, MaxCommandNameLength)...)
+		if stringValue(o.Description) != "" || len(o.DescriptionLocalizations) > 0 {
+			errs = append(errs, fmt.Errorf("description: not allowed on %s commands", CommandTypeName(commandType)))
+		}
+		if len(o.Options) > 0 {
+			errs = append(errs, fmt.Errorf("options: not allowed on %s commands", CommandTypeName(commandType)))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("command %q: %w", o.Name, errors.Join(errs...))
+}
+
+// Validate checks the option, and any options nested within it, against the rules that Discord enforces,
+// returning an error describing every problem found.
+func (o *ApplicationCommandOption) Validate() error {
+	return errors.Join(validateOption(o.Name, o, 0)...)
+}
+
+// validateOptionList checks a list of options. depth is the number of subcommand levels above the options.
+func validateOptionList(path string, options []ApplicationCommandOption, depth int) []error {
+	var errs []error
+	if len(options) > MaxCommandOptions {
+		errs = append(errs, fmt.Errorf("%s: has %d options, but at most %d are allowed", path, len(options), MaxCommandOptions))
+	}
+
+	names := make(map[string]bool, len(options))
+	subcommands := 0
+	optional := false
+	for i := range options {
+		option := &options[i]
+		optionPath := path + "." + option.Name
+		if option.Name == "" {
+			optionPath = fmt.Sprintf("%s.%d", path, i)
+		}
+
+		if names[option.Name] {
+			errs = append(errs, fmt.Errorf("%s: duplicate option name", optionPath))
+		}
+		names[option.Name] = true
+
+		if isSubcommand(option.Type) {
+			subcommands++
+		} else if option.Required != nil && *option.Required {
+			if optional {
+				errs = append(errs, fmt.Errorf("%s: required options must come before optional options", optionPath))
+			}
+		} else {
+			optional = true
+		}
+
+		errs = append(errs, validateOption(optionPath, option, depth)...)
+	}
+
+	if subcommands > 0 && subcommands < len(options) {
+		errs = append(errs, fmt.Errorf("%s: subcommands and subcommand groups cannot be mixed with other options", path))
+	}
+
+	return errs
+}
+
+func validateOption(path string, option *ApplicationCommandOption, depth int) []error {
+	var errs []error
+	errs = append(errs, validateChatInputName(path+".name", option.Name)...)
+	errs = append(errs, validateLocalizations(path+".name_localizations", option.NameLocalizations, validateChatInputName)...)
+	errs = append(errs, validateDescription(path+".description", option.Description)...)
+	errs = append(errs, validateLocalizations(path+".description_localizations", option.DescriptionLocalizations, validateDescription)...)
+
+	switch option.Type {
+	case ApplicationCommandOptionTypeSubCommand, ApplicationCommandOptionTypeSubCommandGroup:
+		errs = append(errs, validateSubcommand(path, option, depth)...)
+		return errs
+	case ApplicationCommandOptionTypeString, ApplicationCommandOptionTypeInteger, ApplicationCommandOptionTypeBoolean,
+		ApplicationCommandOptionTypeUser, ApplicationCommandOptionTypeChannel, ApplicationCommandOptionTypeRole,
+		ApplicationCommandOptionTypeMentionable, ApplicationCommandOptionTypeNumber, ApplicationCommandOptionTypeAttachment:
+	default:
+		errs = append(errs, fmt.Errorf("%s.type: unknown option type %d", path, option.Type))
+	}
+
+	if len(option.Options) > 0 {
+		errs = append(errs, fmt.Errorf("%s.options: only allowed on subcommands and subcommand groups", path))
+	}
+
+	choosable := option.Type == ApplicationCommandOptionTypeString || option.Type == ApplicationCommandOptionTypeInteger || option.Type == ApplicationCommandOptionTypeNumber
+	autocomplete := option.Autocomplete != nil && *option.Autocomplete
+	if len(option.Choices) > 0 && !choosable {
+		errs = append(errs, fmt.Errorf("%s.choices: not allowed on %s options", path, optionTypeName(option.Type)))
+	}
+	if autocomplete && !choosable {
+		errs = append(errs, fmt.Errorf("%s.autocomplete: not allowed on %s options", path, optionTypeName(option.Type)))
+	}
+	if autocomplete && len(option.Choices) > 0 {
+		errs = append(errs, fmt.Errorf("%s: choices cannot be combined with autocomplete", path))
+	}
+	if len(option.Choices) > MaxOptionChoices {
+		errs = append(errs, fmt.Errorf("%s.choices: has %d choices, but at most %d are allowed", path, len(option.Choices), MaxOptionChoices))
+	}
+	if choosable {
+		for i, choice := range option.Choices {
+			errs = append(errs, validateChoice(fmt.Sprintf("%s.choices.%d", path, i), option.Type, choice)...)
+		}
+	}
+
+	if len(option.ChannelTypes) > 0 && option.Type != ApplicationCommandOptionTypeChannel {
+		errs = append(errs, fmt.Errorf("%s.channel_types: not allowed on %s options", path, optionTypeName(option.Type)))
+	}
+
+	numeric := option.Type == ApplicationCommandOptionTypeInteger || option.Type == ApplicationCommandOptionTypeNumber
+	if (option.MinValue != nil || option.MaxValue != nil) && !numeric {
+		errs = append(errs, fmt.Errorf("%s: min_value and max_value are only allowed on integer and number options", path))
+	}
+	if option.MinValue != nil && option.MaxValue != nil && *option.MinValue > *option.MaxValue {
+		errs = append(errs, fmt.Errorf("%s: min_value %v is greater than max_value %v", path, *option.MinValue, *option.MaxValue))
+	}
+
+	if option.MinLength != nil || option.MaxLength != nil {
+		if option.Type != ApplicationCommandOptionTypeString {
+			errs = append(errs, fmt.Errorf("%s: min_length and max_length are only allowed on string options", path))
+		}
+		if option.MinLength != nil && (*option.MinLength < 0 || *option.MinLength > MaxOptionLengthLimit) {
+			errs = append(errs, fmt.Errorf("%s.min_length: must be between 0 and %d", path, MaxOptionLengthLimit))
+		}
+		if option.MaxLength != nil && (*option.MaxLength < 1 || *option.MaxLength > MaxOptionLengthLimit) {
+			errs = append(errs, fmt.Errorf("%s.max_length: must be between 1 and %d", path, MaxOptionLengthLimit))
+		}
+		if option.MinLength != nil && option.MaxLength != nil && *option.MinLength > *option.MaxLength {
+			errs = append(errs, fmt.Errorf("%s: min_length %d is greater than max_length %d", path, *option.MinLength, *option.MaxLength))
+		}
+	}
+
+	return errs
+}
+
+// validateSubcommand checks the parts of a subcommand or subcommand group that differ from other options.
+func validateSubcommand(path string, option *ApplicationCommandOption, depth int) []error {
+	var errs []error
+	if option.Required != nil {
+		errs = append(errs, fmt.Errorf("%s.required: not allowed on %ss", path, optionTypeName(option.Type)))
+	}
+	if len(option.Choices) > 0 || option.Autocomplete != nil || len(option.ChannelTypes) > 0 ||
+		option.MinValue != nil || option.MaxValue != nil || option.MinLength != nil || option.MaxLength != nil {
+		errs = append(errs, fmt.Errorf("%s: only name, description and options are allowed on %ss", path, optionTypeName(option.Type)))
+	}
+
+	if option.Type == ApplicationCommandOptionTypeSubCommandGroup {
+		if depth > 0 {
+			errs = append(errs, fmt.Errorf("%s: subcommand groups are only allowed in the options of a command", path))
+		}
+		if len(option.Options) == 0 {
+			errs = append(errs, fmt.Errorf("%s.options: subcommand groups must have at least one subcommand", path))
+		}
+		for _, subcommand := range option.Options {
+			if subcommand.Type != ApplicationCommandOptionTypeSubCommand {
+				errs = append(errs, fmt.Errorf("%s.options.%s: subcommand groups may only contain subcommands", path, subcommand.Name))
+			}
+		}
+	} else {
+		for _, nested := range option.Options {
+			if isSubcommand(nested.Type) {
+				errs = append(errs, fmt.Errorf("%s.options.%s: subcommands cannot contain subcommands or subcommand groups", path, nested.Name))
+			}
+		}
+	}
+
+	return append(errs, validateOptionList(path+".options", option.Options, depth+1)...)
+}
+
+func validateChoice(path string, optionType int, choice ApplicationCommandOptionChoice) []error {
+	errs := validateLength(path+".name", choice.Name, 1, MaxChoiceNameLength)
+	errs = append(errs, validateLocalizations(path+".name_localizations", choice.NameLocalizations, func(path, name string) []error {
+		return validateLength(path, name, 1, MaxChoiceNameLength)
+	})...)
+
+	switch optionType {
+	case ApplicationCommandOptionTypeString:
+		value, ok := choice.Value.(string)
+		if !ok {
+			return append(errs, fmt.Errorf("%s.value: must be a string, got %T", path, choice.Value))
+		}
+		errs = append(errs, validateLength(path+".value", value, 1, MaxChoiceValueLength)...)
+	case ApplicationCommandOptionTypeInteger:
+		value, ok := numberValue(choice.Value)
+		if !ok || value != math.Trunc(value) {
+			errs = append(errs, fmt.Errorf("%s.value: must be an integer, got %v", path, choice.Value))
+		}
+	case ApplicationCommandOptionTypeNumber:
+		if _, ok := numberValue(choice.Value); !ok {
+			errs = append(errs, fmt.Errorf("%s.value: must be a number, got %T", path, choice.Value))
+		}
+	}
+	return errs
+}
+
+// numberValue converts a choice value to a float64, as it would be after a round trip through JSON.
+func numberValue(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// validateChatInputName checks the name of a chat input command or option,
+// which must be lowercase and may not contain spaces.
+func validateChatInputName(path string, name string) []error {
+	errs := validateLength(path, name, 1, MaxCommandNameLength)
+	for _, r := range name {
+		if r != '-' && r != '_' && !unicode.IsLetter(r) && !unicode.IsNumber(r) {
+			errs = append(errs, fmt.Errorf("%s: %q contains %q, but may only contain letters, numbers, - and _", path, name, r))
+			break
+		}
+		if unicode.ToLower(r) != r {
+			errs = append(errs, fmt.Errorf("%s: %q must be lowercase", path, name))
+			break
+		}
+	}
+	return errs
+}
+
+func validateDescription(path string, description string) []error {
+	return validateLength(path, description, 1, MaxDescriptionLength)
+}
+
+func validateLength(path string, s string, min int, max int) []error {
+	n := utf8.RuneCountInString(s)
+	if n < min || n > max {
+		return []error{fmt.Errorf("%s: %q is %d characters long, but must be between %d and %d", path, s, n, min, max)}
+	}
+	return nil
+}
+
+func validateLocalizations(path string, localizations map[string]string, validate func(path string, s string) []error) []error {
+	var errs []error
+	for _, locale := range sortedKeys(localizations) {
+		errs = append(errs, validate(path+"."+locale, localizations[locale])...)
+	}
+	return errs
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isSubcommand(optionType int) bool {
+	return optionType == ApplicationCommandOptionTypeSubCommand || optionType == ApplicationCommandOptionTypeSubCommandGroup
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,205 @@
+package discord_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func TestValidateCommand(t *testing.T) {
+	stringOption := func(name string) discord.ApplicationCommandOption {
+		return discord.ApplicationCommandOption{Name: name, Description: "An option.", Type: discord.ApplicationCommandOptionTypeString}
+	}
+	chatInput := func(options ...discord.ApplicationCommandOption) *discord.RegisterApplicationCommandOptions {
+		return &discord.RegisterApplicationCommandOptions{Name: "test", Description: discord.String("A test command"), Options: options}
+	}
+
+	tooManyOptions := make([]discord.ApplicationCommandOption, discord.MaxCommandOptions+1)
+	for i := range tooManyOptions {
+		tooManyOptions[i] = stringOption(fmt.Sprintf("option-%d", i))
+	}
+
+	tt := []struct {
+		name    string
+		command *discord.RegisterApplicationCommandOptions
+		// errs are substrings of the expected error, which should be nil if errs is empty.
+		errs []string
+	}{
+		{
+			name: "valid",
+			command: chatInput(
+				discord.ApplicationCommandOption{Name: "length", Description: "A length.", Type: discord.ApplicationCommandOptionTypeInteger, Required: discord.Bool(true), MinValue: discord.Float(0), MaxValue: discord.Float(2000)},
+				discord.ApplicationCommandOption{Name: "size", Description: "A size.", Type: discord.ApplicationCommandOptionTypeInteger, Choices: []discord.ApplicationCommandOptionChoice{{Name: "Small", Value: 1}, {Name: "Large", Value: float64(2)}}},
+				discord.ApplicationCommandOption{Name: "text", Description: "Some text.", Type: discord.ApplicationCommandOptionTypeString, MinLength: discord.Int(1), MaxLength: discord.Int(100), Autocomplete: discord.Bool(true)},
+				discord.ApplicationCommandOption{Name: "channel", Description: "A channel.", Type: discord.ApplicationCommandOptionTypeChannel, ChannelTypes: []int{discord.ChannelTypeGuildText}},
+			),
+		},
+		{
+			name: "valid subcommands",
+			command: chatInput(
+				discord.ApplicationCommandOption{Name: "group", Description: "A group.", Type: discord.ApplicationCommandOptionTypeSubCommandGroup, Options: []discord.ApplicationCommandOption{
+					{Name: "sub", Description: "A subcommand.", Type: discord.ApplicationCommandOptionTypeSubCommand, Options: []discord.ApplicationCommandOption{stringOption("option")}},
+				}},
+				discord.ApplicationCommandOption{Name: "other", Description: "A subcommand.", Type: discord.ApplicationCommandOptionTypeSubCommand},
+			),
+		},
+		{
+			name:    "valid message command",
+			command: &discord.RegisterApplicationCommandOptions{Name: "Checkem this message", Type: discord.Int(discord.ApplicationCommandTypeMessage)},
+		},
+		{
+			name:    "too many options",
+			command: chatInput(tooManyOptions...),
+			errs:    []string{"options: has 26 options, but at most 25 are allowed"},
+		},
+		{
+			name: "choices with autocomplete",
+			command: chatInput(discord.ApplicationCommandOption{
+				Name: "option", Description: "An option.", Type: discord.ApplicationCommandOptionTypeString,
+				Choices: []discord.ApplicationCommandOptionChoice{{Name: "A", Value: "a"}}, Autocomplete: discord.Bool(true),
+			}),
+			errs: []string{"options.option: choices cannot be combined with autocomplete"},
+		},
+		{
+			name: "choice value type",
+			command: chatInput(discord.ApplicationCommandOption{
+				Name: "option", Description: "An option.", Type: discord.ApplicationCommandOptionTypeInteger,
+				Choices: []discord.ApplicationCommandOptionChoice{{Name: "A", Value: "a"}, {Name: "B", Value: 1.5}},
+			}),
+			errs: []string{"options.option.choices.0.value: must be an integer", "options.option.choices.1.value: must be an integer"},
+		},
+		{
+			name: "limits on the wrong type",
+			command: chatInput(discord.ApplicationCommandOption{
+				Name: "option", Description: "An option.", Type: discord.ApplicationCommandOptionTypeBoolean,
+				MinValue: discord.Float(0), MaxLength: discord.Int(10), ChannelTypes: []int{discord.ChannelTypeGuildText},
+			}),
+			errs: []string{
+				"options.option.channel_types: not allowed on boolean options",
+				"options.option: min_value and max_value are only allowed on integer and number options",
+				"options.option: min_length and max_length are only allowed on string options",
+			},
+		},
+		{
+			name: "min greater than max",
+			command: chatInput(discord.ApplicationCommandOption{
+				Name: "option", Description: "An option.", Type: discord.ApplicationCommandOptionTypeNumber,
+				MinValue: discord.Float(10), MaxValue: discord.Float(1),
+			}),
+			errs: []string{"options.option: min_value 10 is greater than max_value 1"},
+		},
+		{
+			name: "required after optional",
+			command: chatInput(
+				stringOption("optional"),
+				discord.ApplicationCommandOption{Name: "required", Description: "An option.", Type: discord.ApplicationCommandOptionTypeString, Required: discord.Bool(true)},
+			),
+			errs: []string{"options.required: required options must come before optional options"},
+		},
+		{
+			name: "subcommands mixed with options",
+			command: chatInput(
+				discord.ApplicationCommandOption{Name: "sub", Description: "A subcommand.", Type: discord.ApplicationCommandOptionTypeSubCommand},
+				stringOption("option"),
+			),
+			errs: []string{"options: subcommands and subcommand groups cannot be mixed with other options"},
+		},
+		{
+			name: "nested subcommand groups",
+			command: chatInput(discord.ApplicationCommandOption{Name: "group", Description: "A group.", Type: discord.ApplicationCommandOptionTypeSubCommandGroup, Options: []discord.ApplicationCommandOption{
+				{Name: "inner", Description: "A group.", Type: discord.ApplicationCommandOptionTypeSubCommandGroup, Options: []discord.ApplicationCommandOption{
+					{Name: "sub", Description: "A subcommand.", Type: discord.ApplicationCommandOptionTypeSubCommand},
+				}},
+			}}),
+			errs: []string{
+				"options.group.options.inner: subcommand groups may only contain subcommands",
+				"options.group.options.inner: subcommand groups are only allowed in the options of a command",
+			},
+		},
+		{
+			name: "invalid names",
+			command: &discord.RegisterApplicationCommandOptions{
+				Name:        "Test Command",
+				Description: discord.String(""),
+				Options:     []discord.ApplicationCommandOption{stringOption(strings.Repeat("a", 33))},
+			},
+			errs: []string{
+				`name: "Test Command" must be lowercase`,
+				"description: \"\" is 0 characters long, but must be between 1 and 100",
+				"is 33 characters long, but must be between 1 and 32",
+			},
+		},
+		{
+			name: "context menu command with options",
+			command: &discord.RegisterApplicationCommandOptions{
+				Name:        "Checkem this message",
+				Type:        discord.Int(discord.ApplicationCommandTypeMessage),
+				Description: discord.String("A description"),
+				Options:     []discord.ApplicationCommandOption{stringOption("option")},
+			},
+			errs: []string{"description: not allowed on message commands", "options: not allowed on message commands"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.command.Validate()
+			if len(tc.errs) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got:\n%s", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected errors %q, got nil", tc.errs)
+			}
+			for _, want := range tc.errs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got:\n%s", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestApplicationCommandOptionRoundTrip(t *testing.T) {
+	raw := `{"name":"option","name_localizations":{"de":"option"},"description":"An option.","type":4,"required":true,` +
+		`"choices":[{"name":"One","name_localizations":{"de":"Eins"},"value":1}],"min_value":0,"max_value":2000}`
+
+	var option discord.ApplicationCommandOption
+	err := json.Unmarshal([]byte(raw), &option)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if option.MinValue == nil || *option.MinValue != 0 || option.MaxValue == nil || *option.MaxValue != 2000 {
+		t.Errorf("expected min_value 0 and max_value 2000, got %v and %v", option.MinValue, option.MaxValue)
+	}
+	if len(option.Choices) != 1 || option.Choices[0].Value != float64(1) {
+		t.Errorf("expected a choice with value 1, got %+v", option.Choices)
+	}
+	if err := option.Validate(); err != nil {
+		t.Errorf("expected the option to be valid, got %s", err)
+	}
+
+	b, err := json.Marshal(option)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got, want map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(raw), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %s, got %s", raw, b)
+	}
+}
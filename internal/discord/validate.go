@@ -0,0 +1,271 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExpectedOption describes an option that a handler reads.
+type ExpectedOption struct {
+	Name string
+	Type int
+	// Required is whether the handler relies on the option always being given.
+	Required bool
+}
+
+// WithOptions declares the options that a command's handler reads,
+// so that they can be checked against the commands registered with Discord by ValidateCommands.
+func WithOptions(options ...ExpectedOption) HandlerOption {
+	return func(o *handlerOptions) {
+		o.options = append([]ExpectedOption{}, options...)
+	}
+}
+
+// RegisteredCommand describes a command that has a handler registered.
+type RegisteredCommand struct {
+	Type int
+	// Name is the name of the command, or its path for subcommands, e.g. "text/left-pad".
+	Name string
+	// Options are the options the handler reads, or nil if they were not declared with WithOptions.
+	Options []ExpectedOption
+	// Autocomplete holds the names of the options that have an autocomplete handler.
+	Autocomplete []string
+}
+
+// RegisteredCommands returns the commands that have a handler registered, sorted by type and name.
+func (h *InteractionsHandler) RegisteredCommands() []RegisteredCommand {
+	var commands []RegisteredCommand
+	for name := range h.applicationCommands {
+		var autocomplete []string
+		for key := range h.autocompletes {
+			if key.command == name {
+				autocomplete = append(autocomplete, key.option)
+			}
+		}
+		sort.Strings(autocomplete)
+
+		commands = append(commands, RegisteredCommand{
+			Type:         ApplicationCommandTypeChatInput,
+			Name:         name,
+			Options:      h.expectedOptions[name],
+			Autocomplete: autocomplete,
+		})
+	}
+	for name := range h.userCommands {
+		commands = append(commands, RegisteredCommand{Type: ApplicationCommandTypeUser, Name: name})
+	}
+	for name := range h.messageCommands {
+		commands = append(commands, RegisteredCommand{Type: ApplicationCommandTypeMessage, Name: name})
+	}
+
+	sort.Slice(commands, func(i, j int) bool {
+		if commands[i].Type != commands[j].Type {
+			return commands[i].Type < commands[j].Type
+		}
+		return commands[i].Name < commands[j].Name
+	})
+	return commands
+}
+
+// ValidateCommands checks the commands registered with Discord against the registered handlers.
+// It returns an error describing every command without a handler, handler without a command,
+// and option that a handler expects but the command does not provide.
+func (h *InteractionsHandler) ValidateCommands(spec []*RegisterApplicationCommandOptions) error {
+	return ValidateCommands(spec, h.RegisteredCommands())
+}
+
+// specLeaf is a command or subcommand that can be invoked, along with its options.
+type specLeaf struct {
+	path    string
+	options []ApplicationCommandOption
+}
+
+// specLeaves returns the invokable commands and subcommands of a chat input command,
+// along with the paths of every command and subcommand group that contains them.
+func specLeaves(path string, options []ApplicationCommandOption, leaves *[]specLeaf, groups map[string]bool) {
+	var subcommands []ApplicationCommandOption
+	for _, option := range options {
+		if option.Type == ApplicationCommandOptionTypeSubCommand || option.Type == ApplicationCommandOptionTypeSubCommandGroup {
+			subcommands = append(subcommands, option)
+		}
+	}
+
+	if len(subcommands) == 0 {
+		*leaves = append(*leaves, specLeaf{path: path, options: options})
+		return
+	}
+
+	groups[path] = true
+	for _, subcommand := range subcommands {
+		specLeaves(path+"/"+subcommand.Name, subcommand.Options, leaves, groups)
+	}
+}
+
+// ValidateCommands checks the commands in spec against the given registered commands.
+// See InteractionsHandler.ValidateCommands.
+func ValidateCommands(spec []*RegisterApplicationCommandOptions, registered []RegisteredCommand) error {
+	handlers := make(map[int]map[string]RegisteredCommand)
+	for _, command := range registered {
+		if handlers[command.Type] == nil {
+			handlers[command.Type] = make(map[string]RegisteredCommand)
+		}
+		handlers[command.Type][command.Name] = command
+	}
+
+	var errs []error
+	inSpec := make(map[int]map[string]bool)
+	markInSpec := func(commandType int, name string) {
+		if inSpec[commandType] == nil {
+			inSpec[commandType] = make(map[string]bool)
+		}
+		inSpec[commandType][name] = true
+	}
+
+	for _, command := range spec {
+		commandType := ApplicationCommandTypeChatInput
+		if command.Type != nil {
+			commandType = *command.Type
+		}
+
+		if commandType != ApplicationCommandTypeChatInput {
+			markInSpec(commandType, command.Name)
+			if _, ok := handlers[commandType][command.Name]; !ok {
+				errs = append(errs, fmt.Errorf("%s command %q has no handler", CommandTypeName(commandType), command.Name))
+			}
+			continue
+		}
+
+		var leaves []specLeaf
+		groups := make(map[string]bool)
+		specLeaves(command.Name, command.Options, &leaves, groups)
+		for group := range groups {
+			markInSpec(commandType, group)
+		}
+
+		for _, leaf := range leaves {
+			markInSpec(commandType, leaf.path)
+
+			handler, ok := findHandler(handlers[commandType], leaf.path)
+			if !ok {
+				errs = append(errs, fmt.Errorf("command %q has no handler", leaf.path))
+				continue
+			}
+
+			errs = append(errs, validateOptions(leaf, handler)...)
+		}
+	}
+
+	for _, command := range registered {
+		if !inSpec[command.Type][command.Name] {
+			errs = append(errs, fmt.Errorf("handler for %s command %q has no command in the spec", CommandTypeName(command.Type), command.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// findHandler finds the handler for a command path, falling back to the handlers of its parents as routing does.
+func findHandler(handlers map[string]RegisteredCommand, path string) (RegisteredCommand, bool) {
+	for {
+		if handler, ok := handlers[path]; ok {
+			return handler, true
+		}
+
+		i := strings.LastIndex(path, "/")
+		if i < 0 {
+			return RegisteredCommand{}, false
+		}
+		path = path[:i]
+	}
+}
+
+// validateOptions checks the options a handler expects against the options of the command it handles.
+func validateOptions(leaf specLeaf, handler RegisteredCommand) []error {
+	specOptions := make(map[string]ApplicationCommandOption, len(leaf.options))
+	for _, option := range leaf.options {
+		specOptions[option.Name] = option
+	}
+
+	var errs []error
+	for _, expected := range handler.Options {
+		option, ok := specOptions[expected.Name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("command %q: handler reads option %q, which is not in the spec", leaf.path, expected.Name))
+			continue
+		}
+
+		if option.Type != expected.Type {
+			errs = append(errs, fmt.Errorf("command %q: option %q has type %s in the spec, but the handler expects %s", leaf.path, expected.Name, optionTypeName(option.Type), optionTypeName(expected.Type)))
+		}
+
+		if expected.Required && (option.Required == nil || !*option.Required) {
+			errs = append(errs, fmt.Errorf("command %q: option %q is required by the handler, but optional in the spec", leaf.path, expected.Name))
+		}
+	}
+
+	if handler.Options != nil {
+		declared := make(map[string]bool, len(handler.Options))
+		for _, expected := range handler.Options {
+			declared[expected.Name] = true
+		}
+		for _, option := range leaf.options {
+			if option.Required != nil && *option.Required && !declared[option.Name] {
+				errs = append(errs, fmt.Errorf("command %q: required option %q is not read by the handler", leaf.path, option.Name))
+			}
+		}
+	}
+
+	for _, name := range handler.Autocomplete {
+		option, ok := specOptions[name]
+		if !ok || option.Autocomplete == nil || !*option.Autocomplete {
+			errs = append(errs, fmt.Errorf("command %q: option %q has an autocomplete handler, but autocomplete is not enabled in the spec", leaf.path, name))
+		}
+	}
+
+	return errs
+}
+
+// CommandTypeName returns a short name for an application command type, as used in messages, e.g. "slash" or "message".
+func CommandTypeName(commandType int) string {
+	switch commandType {
+	case ApplicationCommandTypeChatInput:
+		return "slash"
+	case ApplicationCommandTypeUser:
+		return "user"
+	case ApplicationCommandTypeMessage:
+		return "message"
+	default:
+		return fmt.Sprintf("type %d", commandType)
+	}
+}
+
+func optionTypeName(optionType int) string {
+	switch optionType {
+	case ApplicationCommandOptionTypeSubCommand:
+		return "subcommand"
+	case ApplicationCommandOptionTypeSubCommandGroup:
+		return "subcommand group"
+	case ApplicationCommandOptionTypeString:
+		return "string"
+	case ApplicationCommandOptionTypeInteger:
+		return "integer"
+	case ApplicationCommandOptionTypeBoolean:
+		return "boolean"
+	case ApplicationCommandOptionTypeUser:
+		return "user"
+	case ApplicationCommandOptionTypeChannel:
+		return "channel"
+	case ApplicationCommandOptionTypeRole:
+		return "role"
+	case ApplicationCommandOptionTypeMentionable:
+		return "mentionable"
+	case ApplicationCommandOptionTypeNumber:
+		return "number"
+	case ApplicationCommandOptionTypeAttachment:
+		return "attachment"
+	default:
+		return fmt.Sprintf("type %d", optionType)
+	}
+}
@@ -0,0 +1,158 @@
+package discord_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brattonross/ghostedbot/internal/discord"
+)
+
+func noopHandler(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+	return discord.MessageResponse("ok"), nil
+}
+
+func TestRegisteredCommands(t *testing.T) {
+	handler := discord.NewInteractionsHandler(nil)
+	handler.RegisterApplicationCommandHandler("mdn", noopHandler, discord.WithOptions(
+		discord.ExpectedOption{Name: "query", Type: discord.ApplicationCommandOptionTypeString, Required: true},
+	))
+	handler.RegisterAutocompleteHandler("mdn", "query", func(ctx *discord.InteractionContext, value string) ([]discord.ApplicationCommandOptionChoice, error) {
+		return nil, nil
+	})
+	handler.Group("text").RegisterApplicationCommandHandler("shuffle", noopHandler)
+	handler.RegisterMessageCommandHandler("Shuffle this message", func(ctx *discord.InteractionContext, message *discord.Message) (*discord.InteractionResponse, error) {
+		return nil, nil
+	})
+
+	expected := []discord.RegisteredCommand{
+		{
+			Type:         discord.ApplicationCommandTypeChatInput,
+			Name:         "mdn",
+			Options:      []discord.ExpectedOption{{Name: "query", Type: discord.ApplicationCommandOptionTypeString, Required: true}},
+			Autocomplete: []string{"query"},
+		},
+		{Type: discord.ApplicationCommandTypeChatInput, Name: "text/shuffle"},
+		{Type: discord.ApplicationCommandTypeMessage, Name: "Shuffle this message"},
+	}
+
+	if got := handler.RegisteredCommands(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected registered commands %+v, got %+v", expected, got)
+	}
+}
+
+func TestValidateCommands(t *testing.T) {
+	spec := []*discord.RegisterApplicationCommandOptions{
+		{
+			Name: "left-pad",
+			Options: []discord.ApplicationCommandOption{
+				{Name: "length", Type: discord.ApplicationCommandOptionTypeInteger, Required: discord.Bool(true)},
+				{Name: "message", Type: discord.ApplicationCommandOptionTypeString},
+			},
+		},
+		{
+			Name: "text",
+			Options: []discord.ApplicationCommandOption{
+				{
+					Name: "shuffle",
+					Type: discord.ApplicationCommandOptionTypeSubCommand,
+					Options: []discord.ApplicationCommandOption{
+						{Name: "message", Type: discord.ApplicationCommandOptionTypeString},
+					},
+				},
+			},
+		},
+		{Name: "Shuffle this message", Type: discord.Int(discord.ApplicationCommandTypeMessage)},
+	}
+
+	tt := []struct {
+		name     string
+		register func(h *discord.InteractionsHandler)
+		errors   []string
+	}{
+		{
+			name: "matching",
+			register: func(h *discord.InteractionsHandler) {
+				h.RegisterApplicationCommandHandler("left-pad", noopHandler, discord.WithOptions(
+					discord.ExpectedOption{Name: "length", Type: discord.ApplicationCommandOptionTypeInteger, Required: true},
+					discord.ExpectedOption{Name: "message", Type: discord.ApplicationCommandOptionTypeString},
+				))
+				h.Group("text").RegisterApplicationCommandHandler("shuffle", noopHandler)
+				h.RegisterMessageCommandHandler("Shuffle this message", nil)
+			},
+		},
+		{
+			name: "parent handles subcommands",
+			register: func(h *discord.InteractionsHandler) {
+				h.RegisterApplicationCommandHandler("left-pad", noopHandler)
+				h.RegisterApplicationCommandHandler("text", noopHandler)
+				h.RegisterMessageCommandHandler("Shuffle this message", nil)
+			},
+		},
+		{
+			name: "mismatched names",
+			register: func(h *discord.InteractionsHandler) {
+				h.RegisterApplicationCommandHandler("leftpad", noopHandler)
+				h.Group("text").RegisterApplicationCommandHandler("shuffle", noopHandler)
+				h.RegisterMessageCommandHandler("Shuffle this message", nil)
+			},
+			errors: []string{
+				`command "left-pad" has no handler`,
+				`handler for slash command "leftpad" has no command in the spec`,
+			},
+		},
+		{
+			name: "mismatched options",
+			register: func(h *discord.InteractionsHandler) {
+				h.RegisterApplicationCommandHandler("left-pad", noopHandler, discord.WithOptions(
+					discord.ExpectedOption{Name: "message", Type: discord.ApplicationCommandOptionTypeInteger, Required: true},
+					discord.ExpectedOption{Name: "character", Type: discord.ApplicationCommandOptionTypeString},
+				))
+				h.RegisterAutocompleteHandler("left-pad", "message", nil)
+				h.Group("text").RegisterApplicationCommandHandler("shuffle", noopHandler)
+				h.RegisterMessageCommandHandler("Shuffle this message", nil)
+			},
+			errors: []string{
+				`command "left-pad": option "message" has type string in the spec, but the handler expects integer`,
+				`command "left-pad": option "message" is required by the handler, but optional in the spec`,
+				`command "left-pad": handler reads option "character", which is not in the spec`,
+				`command "left-pad": required option "length" is not read by the handler`,
+				`command "left-pad": option "message" has an autocomplete handler, but autocomplete is not enabled in the spec`,
+			},
+		},
+		{
+			name: "missing context menu handler",
+			register: func(h *discord.InteractionsHandler) {
+				h.RegisterApplicationCommandHandler("left-pad", noopHandler)
+				h.RegisterApplicationCommandHandler("text", noopHandler)
+			},
+			errors: []string{
+				`message command "Shuffle this message" has no handler`,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := discord.NewInteractionsHandler(nil)
+			tc.register(handler)
+
+			err := handler.ValidateCommands(spec)
+			if len(tc.errors) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+
+			got := strings.Split(err.Error(), "\n")
+			if !reflect.DeepEqual(got, tc.errors) {
+				t.Errorf("expected errors:\n%s\ngot:\n%s", strings.Join(tc.errors, "\n"), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// InteractionWebhooksClient edits interaction responses and sends follow-up messages.
+// These endpoints are authenticated by the interaction token rather than the bot token.
+type InteractionWebhooksClient service
+
+// EditOriginalResponse edits the initial response to an interaction.
+func (c *InteractionWebhooksClient) EditOriginalResponse(ctx context.Context, applicationId string, token string, data *InteractionResponseData) (*Message, error) {
+	var message Message
+	err := c.client.do(ctx, http.MethodPatch, fmt.Sprintf("webhooks/%s/%s/messages/@original", applicationId, token), data, &message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// DeleteOriginalResponse deletes the initial response to an interaction.
+func (c *InteractionWebhooksClient) DeleteOriginalResponse(ctx context.Context, applicationId string, token string) error {
+	return c.client.do(ctx, http.MethodDelete, fmt.Sprintf("webhooks/%s/%s/messages/@original", applicationId, token), nil, nil)
+}
+
+// CreateFollowupMessage sends a new message in response to an interaction.
+func (c *InteractionWebhooksClient) CreateFollowupMessage(ctx context.Context, applicationId string, token string, data *InteractionResponseData) (*Message, error) {
+	var message Message
+	err := c.client.do(ctx, http.MethodPost, fmt.Sprintf("webhooks/%s/%s", applicationId, token), data, &message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
@@ -1,27 +1,37 @@
 package mdn
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/brattonross/ghostedbot/internal/discord"
 )
 
 type document struct {
-	Title string `json:"title"`
-	Slug  string `json:"slug"`
+	Title   string `json:"title"`
+	Slug    string `json:"slug"`
+	Summary string `json:"summary"`
 }
 
 type searchResponse struct {
 	Documents []*document `json:"documents"`
 }
 
-func search(query string) (*searchResponse, error) {
-	res, err := http.Get(fmt.Sprintf("https://developer.mozilla.org/api/v1/search?q=%s&locale=en-US", query))
+func search(ctx context.Context, query string) (*searchResponse, error) {
+	u := fmt.Sprintf("https://developer.mozilla.org/api/v1/search?q=%s&locale=en-US", url.QueryEscape(query))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MDN search request: %w", err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search MDN: %w", err)
 	}
+	defer res.Body.Close()
 
 	var searchResults searchResponse
 	err = json.NewDecoder(res.Body).Decode(&searchResults)
@@ -32,22 +42,92 @@ func search(query string) (*searchResponse, error) {
 	return &searchResults, nil
 }
 
+// embedColor is the color of MDN's branding.
+const embedColor = 0x1b1b1b
+
+// Command is the mdn application command.
+var Command = &discord.Command{
+	Name:        "mdn",
+	Description: "Searches MDN for the given query, returning the link for the first matched article.",
+	Options: []discord.ApplicationCommandOption{
+		{
+			Name:         "query",
+			Description:  "The query to search for.",
+			Type:         discord.ApplicationCommandOptionTypeString,
+			Required:     discord.Bool(true),
+			Autocomplete: discord.Bool(true),
+		},
+	},
+	Handler: SearchHandler,
+	Autocomplete: map[string]discord.AutocompleteHandlerFunc{
+		"query": AutocompleteHandler,
+	},
+}
+
 // SearchHandler is a discord application command handler that searches MDN for a given query.
 func SearchHandler(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
-	if len(ctx.Interaction.Data.Options) < 1 {
-		return discord.MessageResponse("Please provide a search query"), nil
+	query, ok := ctx.StringOption("query")
+	if !ok {
+		return discord.EphemeralResponse("Please provide a search query"), nil
+	}
+
+	// MDN can take longer to respond than Discord is willing to wait, so search in the background.
+	return discord.DeferredMessageResponse(func(ctx *discord.InteractionContext) (*discord.InteractionResponseData, error) {
+		resp, err := search(ctx.Context(), query)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resp.Documents) < 1 {
+			return &discord.InteractionResponseData{Content: discord.String("No articles found")}, nil
+		}
+
+		doc := resp.Documents[0]
+		embed, err := discord.NewEmbed().
+			SetTitle(truncate(doc.Title, discord.MaxEmbedTitleLength)).
+			SetURL(fmt.Sprintf("https://developer.mozilla.org/en-US/docs/%s", doc.Slug)).
+			SetDescription(truncate(doc.Summary, discord.MaxEmbedDescriptionLength)).
+			SetColor(embedColor).
+			SetFooter("MDN Web Docs", "").
+			Build()
+		if err != nil {
+			return nil, err
+		}
+
+		return &discord.InteractionResponseData{Embeds: []*discord.Embed{embed}}, nil
+	}), nil
+}
+
+// maxChoiceLength is the maximum length Discord allows for a choice name or value.
+const maxChoiceLength = 100
+
+func truncate(s string, length int) string {
+	r := []rune(s)
+	if len(r) <= length {
+		return s
+	}
+	return string(r[:length])
+}
+
+// AutocompleteHandler is a discord autocomplete handler that suggests MDN article titles for a partial query.
+func AutocompleteHandler(ctx *discord.InteractionContext, value string) ([]discord.ApplicationCommandOptionChoice, error) {
+	if value == "" {
+		return nil, nil
 	}
 
-	query := ctx.Interaction.Data.Options[0].Value.(string)
-	resp, err := search(query)
+	resp, err := search(ctx.Context(), value)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(resp.Documents) < 1 {
-		return discord.MessageResponse("No articles found"), nil
+	choices := make([]discord.ApplicationCommandOptionChoice, 0, len(resp.Documents))
+	for _, doc := range resp.Documents {
+		title := truncate(doc.Title, maxChoiceLength)
+		choices = append(choices, discord.ApplicationCommandOptionChoice{
+			Name:  title,
+			Value: title,
+		})
 	}
 
-	message := fmt.Sprintf("%s: https://developer.mozilla.org/en-US/docs/%s", resp.Documents[0].Title, resp.Documents[0].Slug)
-	return discord.MessageResponse(message), nil
+	return choices, nil
 }
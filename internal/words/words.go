@@ -1,9 +1,12 @@
 package words
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/brattonross/ghostedbot/internal/discord"
 )
@@ -20,14 +23,110 @@ func LeftPad(s string, length int, char string) string {
 	return char[:length] + s
 }
 
+// textMessage creates a message containing s, sending it as a text file instead if it is too long for a message.
+func textMessage(s string) *discord.InteractionResponseData {
+	return &discord.InteractionResponseData{
+		Content:  discord.String(s),
+		Overflow: discord.ContentOverflowAttach,
+	}
+}
+
+// MaxLeftPadLength is the longest length that a message can be left-padded to.
+const MaxLeftPadLength = discord.MaxContentLength
+
+// leftPadLengthError returns an error message if length is out of range, or nil if it is valid.
+func leftPadLengthError(length int) *discord.InteractionResponse {
+	if length < 0 || length > MaxLeftPadLength {
+		return discord.EphemeralResponse(fmt.Sprintf("Length must be between 0 and %d.", MaxLeftPadLength))
+	}
+	return nil
+}
+
+// leftPadMessage left-pads s, or returns an error message if length is out of range.
+func leftPadMessage(s string, length int, char string) *discord.InteractionResponse {
+	if res := leftPadLengthError(length); res != nil {
+		return res
+	}
+
+	return &discord.InteractionResponse{
+		Type: discord.InteractionResponseTypeChannelMessageWithSource,
+		Data: textMessage(LeftPad(s, length, char)),
+	}
+}
+
+// LeftPadCommand is the left-pad application command.
+var LeftPadCommand = &discord.Command{
+	Name:        "left-pad",
+	Description: "Left-pads a message",
+	Options: []discord.ApplicationCommandOption{
+		{
+			Name:        "length",
+			Description: "The length to pad to.",
+			Type:        discord.ApplicationCommandOptionTypeInteger,
+			Required:    discord.Bool(true),
+			MinValue:    discord.Float(0),
+			MaxValue:    discord.Float(MaxLeftPadLength),
+		},
+		{
+			Name:        "message",
+			Description: "The message to pad. Leave empty to enter a multi-line message.",
+			Type:        discord.ApplicationCommandOptionTypeString,
+			Required:    discord.Bool(false),
+		},
+		{
+			Name:        "character",
+			Description: "The character to pad with.",
+			Type:        discord.ApplicationCommandOptionTypeString,
+			Required:    discord.Bool(false),
+		},
+	},
+	Handler: LeftPadHandler,
+}
+
+// LeftPadModalCustomId is the custom ID of the modal used to enter a multi-line message to left-pad.
+const LeftPadModalCustomId = "left-pad"
+
 func LeftPadHandler(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
-	str := ctx.Interaction.Data.Options[0].Value.(string)
-	length := int(ctx.Interaction.Data.Options[1].Value.(float64))
-	char := ""
-	if len(ctx.Interaction.Data.Options) > 2 {
-		char = ctx.Interaction.Data.Options[2].Value.(string)
+	length, ok := ctx.IntOption("length")
+	if !ok {
+		return discord.EphemeralResponse("Please provide a length to pad to."), nil
+	}
+	if res := leftPadLengthError(length); res != nil {
+		return res, nil
+	}
+	char, _ := ctx.StringOption("character")
+
+	str, ok := ctx.StringOption("message")
+	if !ok {
+		lengthInput := discord.NewTextInput("length", "Length", discord.TextInputStyleShort)
+		lengthInput.Value = strconv.Itoa(length)
+		charInput := discord.NewTextInput("character", "Character", discord.TextInputStyleShort)
+		charInput.Value = char
+		charInput.Required = discord.Bool(false)
+
+		return discord.ModalResponse(LeftPadModalCustomId, "Left-pad",
+			discord.NewActionRow(discord.NewTextInput("message", "Message", discord.TextInputStyleParagraph)),
+			discord.NewActionRow(lengthInput),
+			discord.NewActionRow(charInput),
+		), nil
 	}
-	return discord.MessageResponse(LeftPad(str, length, char)), nil
+
+	return leftPadMessage(str, length, char), nil
+}
+
+// LeftPadModalHandler is a discord modal handler that left-pads the message entered in the left-pad modal.
+func LeftPadModalHandler(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+	data := ctx.Interaction.ModalSubmitData()
+	str, _ := data.TextInputValue("message")
+	char, _ := data.TextInputValue("character")
+
+	value, _ := data.TextInputValue("length")
+	length, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return discord.EphemeralResponse("Length must be a whole number."), nil
+	}
+
+	return leftPadMessage(str, length, char), nil
 }
 
 // Shuffle shuffles the words in a given string, using space as a delimiter.
@@ -39,10 +138,98 @@ func Shuffle(s string) string {
 	return strings.Join(words, " ")
 }
 
+// ShuffleCommand is the shuffle application command.
+var ShuffleCommand = &discord.Command{
+	Name:        "shuffle",
+	Description: "Shuffles the provided message, word by word.",
+	Options: []discord.ApplicationCommandOption{
+		{
+			Name:        "message",
+			Description: "The message to shuffle. Leave empty to enter a multi-line message.",
+			Type:        discord.ApplicationCommandOptionTypeString,
+			Required:    discord.Bool(false),
+		},
+	},
+	Handler: ShuffleHandler,
+}
+
+// ShuffleMessageCommand is the message context menu command that shuffles the targeted message.
+var ShuffleMessageCommand = &discord.Command{
+	Type:           discord.ApplicationCommandTypeMessage,
+	Name:           "Shuffle this message",
+	MessageHandler: ShuffleMessageHandler,
+}
+
+// TextCommand groups the text tools as subcommands.
+var TextCommand = &discord.Command{
+	Name:        "text",
+	Description: "Text tools.",
+	Subcommands: []*discord.Command{LeftPadCommand, ShuffleCommand},
+}
+
 func ShuffleHandler(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
-	if len(ctx.Interaction.Data.Options) < 1 {
-		return discord.MessageResponse("Please provide a string to shuffle."), nil
+	str, ok := ctx.StringOption("message")
+	if !ok {
+		return discord.ModalResponse(ShuffleModalCustomId, "Shuffle",
+			discord.NewActionRow(discord.NewTextInput("message", "Message", discord.TextInputStyleParagraph)),
+		), nil
+	}
+
+	return &discord.InteractionResponse{
+		Type: discord.InteractionResponseTypeChannelMessageWithSource,
+		Data: shuffledMessage(str),
+	}, nil
+}
+
+// ShuffleMessageHandler is a discord message command handler that shuffles the content of the targeted message.
+func ShuffleMessageHandler(ctx *discord.InteractionContext, message *discord.Message) (*discord.InteractionResponse, error) {
+	if message.Content == "" {
+		return discord.EphemeralResponse("That message has no text to shuffle."), nil
+	}
+
+	return &discord.InteractionResponse{
+		Type: discord.InteractionResponseTypeChannelMessageWithSource,
+		Data: shuffledMessage(message.Content),
+	}, nil
+}
+
+// ShuffleModalCustomId is the custom ID of the modal used to enter a multi-line message to shuffle.
+const ShuffleModalCustomId = "shuffle"
+
+// ShuffleModalHandler is a discord modal handler that shuffles the message entered in the shuffle modal.
+func ShuffleModalHandler(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+	str, ok := ctx.Interaction.ModalSubmitData().TextInputValue("message")
+	if !ok || str == "" {
+		return discord.EphemeralResponse("Please provide a string to shuffle."), nil
+	}
+
+	return &discord.InteractionResponse{
+		Type: discord.InteractionResponseTypeChannelMessageWithSource,
+		Data: shuffledMessage(str),
+	}, nil
+}
+
+// ReshuffleCustomId is the custom ID of the button attached to shuffled messages.
+const ReshuffleCustomId = "shuffle:reshuffle"
+
+func shuffledMessage(s string) *discord.InteractionResponseData {
+	data := textMessage(Shuffle(s))
+	if utf8.RuneCountInString(s) > discord.MaxContentLength {
+		// the reshuffle button works from the message content, which only holds a note when the result is a file.
+		return data
+	}
+
+	data.Components = []discord.ActionRow{
+		discord.NewActionRow(discord.NewButton(discord.ButtonStyleSecondary, "Reshuffle", ReshuffleCustomId)),
+	}
+	return data
+}
+
+// ReshuffleHandler is a discord component handler that shuffles a previously shuffled message again.
+func ReshuffleHandler(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
+	if ctx.Interaction.Message == nil {
+		return discord.EphemeralResponse("Could not find the message to reshuffle."), nil
 	}
 
-	return discord.MessageResponse(Shuffle(ctx.Interaction.Data.Options[0].Value.(string))), nil
+	return discord.UpdateMessageResponse(shuffledMessage(ctx.Interaction.Message.Content)), nil
 }
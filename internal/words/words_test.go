@@ -1,8 +1,11 @@
 package words_test
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/brattonross/ghostedbot/internal/discord"
 	"github.com/brattonross/ghostedbot/internal/words"
 )
 
@@ -60,3 +63,143 @@ func TestLeftPad(t *testing.T) {
 		})
 	}
 }
+
+func commandContext(options ...discord.ApplicationCommandInteractionDataOption) *discord.InteractionContext {
+	return &discord.InteractionContext{
+		Interaction: &discord.Interaction{
+			Type: discord.InteractionTypeApplicationCommand,
+			Data: &discord.ApplicationCommandInteractionData{Name: "left-pad", Options: options},
+		},
+	}
+}
+
+func lengthOption(length int) discord.ApplicationCommandInteractionDataOption {
+	// JSON numbers are always decoded as float64.
+	return discord.ApplicationCommandInteractionDataOption{Name: "length", Type: discord.ApplicationCommandOptionTypeInteger, Value: float64(length)}
+}
+
+func messageOption(message string) discord.ApplicationCommandInteractionDataOption {
+	return discord.ApplicationCommandInteractionDataOption{Name: "message", Type: discord.ApplicationCommandOptionTypeString, Value: message}
+}
+
+func TestLeftPadHandlerLength(t *testing.T) {
+	tt := []struct {
+		length  int
+		wantErr bool
+	}{
+		{length: -1, wantErr: true},
+		{length: 0},
+		{length: words.MaxLeftPadLength},
+		{length: words.MaxLeftPadLength + 1, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(strconv.Itoa(tc.length), func(t *testing.T) {
+			res, err := words.LeftPadHandler(commandContext(lengthOption(tc.length), messageOption("test")))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.wantErr {
+				if res.Data == nil || !res.Data.Flags.Has(discord.MessageFlagEphemeral) {
+					t.Errorf("expected an ephemeral error message, got %+v", res.Data)
+				}
+				return
+			}
+
+			want := words.LeftPad("test", tc.length, "")
+			if res.Type != discord.InteractionResponseTypeChannelMessageWithSource || res.Data == nil || res.Data.Content == nil || *res.Data.Content != want {
+				t.Errorf("expected a message of length %d, got %+v", len(want), res.Data)
+			}
+		})
+	}
+}
+
+func TestLeftPadHandlerOptions(t *testing.T) {
+	character := discord.ApplicationCommandInteractionDataOption{Name: "character", Type: discord.ApplicationCommandOptionTypeString, Value: "x"}
+
+	tt := []struct {
+		name    string
+		options []discord.ApplicationCommandInteractionDataOption
+		want    string
+	}{
+		{
+			name:    "without character",
+			options: []discord.ApplicationCommandInteractionDataOption{lengthOption(6), messageOption("test")},
+			want:    "  test",
+		},
+		{
+			name:    "reordered without character",
+			options: []discord.ApplicationCommandInteractionDataOption{messageOption("test"), lengthOption(6)},
+			want:    "  test",
+		},
+		{
+			name:    "reordered with character",
+			options: []discord.ApplicationCommandInteractionDataOption{character, messageOption("test"), lengthOption(6)},
+			want:    "xxtest",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := words.LeftPadHandler(commandContext(tc.options...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Data == nil || res.Data.Content == nil || *res.Data.Content != tc.want {
+				t.Errorf("expected content %q, got %+v", tc.want, res.Data)
+			}
+		})
+	}
+}
+
+func TestLeftPadModal(t *testing.T) {
+	res, err := words.LeftPadHandler(commandContext(lengthOption(10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Type != discord.InteractionResponseTypeModal || res.Data == nil || res.Data.CustomId == nil || *res.Data.CustomId != words.LeftPadModalCustomId {
+		t.Fatalf("expected the left-pad modal, got %+v", res)
+	}
+
+	modalContext := func(length string) *discord.InteractionContext {
+		return &discord.InteractionContext{
+			Interaction: &discord.Interaction{
+				Type: discord.InteractionTypeModalSubmit,
+				Data: &discord.ModalSubmitInteractionData{
+					CustomId: words.LeftPadModalCustomId,
+					Components: []discord.ActionRow{
+						discord.NewActionRow(&discord.TextInput{CustomId: "message", Value: "line one\nline two"}),
+						discord.NewActionRow(&discord.TextInput{CustomId: "length", Value: length}),
+						discord.NewActionRow(&discord.TextInput{CustomId: "character", Value: "x"}),
+					},
+				},
+			},
+		}
+	}
+
+	tt := []struct {
+		length string
+		want   string
+	}{
+		{length: "20", want: "xxx" + "line one\nline two"},
+		{length: "-1", want: "Length must be between 0 and 2000."},
+		{length: strconv.Itoa(words.MaxLeftPadLength), want: strings.Repeat("x", words.MaxLeftPadLength-17) + "line one\nline two"},
+		{length: strconv.Itoa(words.MaxLeftPadLength + 1), want: "Length must be between 0 and 2000."},
+		{length: "ten", want: "Length must be a whole number."},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.length, func(t *testing.T) {
+			res, err := words.LeftPadModalHandler(modalContext(tc.length))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Data == nil || res.Data.Content == nil || *res.Data.Content != tc.want {
+				t.Errorf("expected content %q, got %+v", tc.want, res.Data)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package progress
 
 import (
+	"bytes"
 	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
 	"math"
 	"strings"
 	"time"
@@ -31,7 +36,69 @@ func ToBar(percentage float64) string {
 	return fmt.Sprintf("%s%s", strings.Repeat(filled, filledCount), strings.Repeat(empty, emptyCount))
 }
 
+const (
+	imageWidth  = 400
+	imageHeight = 40
+)
+
+var (
+	filledColor = color.RGBA{R: 0x58, G: 0x65, B: 0xf2, A: 0xff}
+	emptyColor  = color.RGBA{R: 0x2b, G: 0x2d, B: 0x31, A: 0xff}
+)
+
+// RenderBar renders a progress bar for the given percentage as a PNG image.
+func RenderBar(percentage float64) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, imageWidth, imageHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: emptyColor}, image.Point{}, draw.Src)
+
+	filledWidth := int(math.Round(imageWidth * math.Max(0, math.Min(percentage, 100)) / 100))
+	draw.Draw(img, image.Rect(0, 0, filledWidth, imageHeight), &image.Uniform{C: filledColor}, image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+const imageFilename = "year-progress.png"
+
 func PercentageHandler(ctx *discord.InteractionContext) (*discord.InteractionResponse, error) {
-	percentage := Percentage(time.Now().UTC().Unix())
-	return discord.MessageResponse(fmt.Sprintf("%s %v%%", ToBar(percentage), percentage)), nil
+	now := time.Now().UTC()
+	percentage := Percentage(now.Unix())
+
+	bar, err := RenderBar(percentage)
+	if err != nil {
+		return nil, err
+	}
+
+	embed, err := discord.NewEmbed().
+		SetTitle(fmt.Sprintf("%d progress", now.Year())).
+		SetDescription(fmt.Sprintf("%s %v%%", ToBar(percentage), percentage)).
+		SetImage("attachment://" + imageFilename).
+		SetTimestamp(now).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	res := discord.EmbedResponse(embed)
+	res.Data.Files = []*discord.File{
+		{
+			Name:        imageFilename,
+			ContentType: "image/png",
+			Description: fmt.Sprintf("%v%% of the year has passed", percentage),
+			Reader:      bytes.NewReader(bar),
+		},
+	}
+	return res, nil
+}
+
+// Command is the year-progress application command.
+var Command = &discord.Command{
+	Name:        "year-progress",
+	Description: "Prints a progress bar that shows how far through the year we are.",
+	Handler:     PercentageHandler,
 }
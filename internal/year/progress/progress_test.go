@@ -1,6 +1,8 @@
 package progress_test
 
 import (
+	"bytes"
+	"image/png"
 	"testing"
 
 	"github.com/brattonross/ghostedbot/internal/year/progress"
@@ -71,3 +73,27 @@ func TestToBar(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderBar(t *testing.T) {
+	b, err := progress.RenderBar(50)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bounds := img.Bounds()
+	left := img.At(bounds.Min.X, bounds.Min.Y)
+	right := img.At(bounds.Max.X-1, bounds.Min.Y)
+	if left == right {
+		t.Errorf("expected the filled and empty parts of the bar to differ, both were %v", left)
+	}
+
+	middle := img.At(bounds.Dx()/2-1, bounds.Min.Y)
+	if middle != left {
+		t.Errorf("expected the bar to be filled up to the middle, got %v at the middle and %v on the left", middle, left)
+	}
+}